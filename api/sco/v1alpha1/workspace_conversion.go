@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"unsafe"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+)
+
+// v1alpha1 and v1beta1 share the same schema, v1beta1 only becomes the storage
+// version. As their types have the same memory layout, they are converted the way
+// conversion-gen does for identical types, out of a copy so that the versions do
+// not share any state. TestWorkspaceSchema makes sure the schemas do not diverge.
+
+// ConvertTo converts this Workspace to the hub version.
+func (in *Workspace) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.Workspace)
+	if !ok {
+		return fmt.Errorf("unsupported conversion to %T", dstRaw)
+	}
+
+	src := in.DeepCopy()
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = *(*v1beta1.WorkspaceSpec)(unsafe.Pointer(&src.Spec))
+	dst.Status = *(*v1beta1.WorkspaceStatus)(unsafe.Pointer(&src.Status))
+
+	return nil
+}
+
+// ConvertFrom converts from the hub version to this Workspace.
+func (in *Workspace) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.Workspace)
+	if !ok {
+		return fmt.Errorf("unsupported conversion from %T", srcRaw)
+	}
+
+	src = src.DeepCopy()
+
+	in.ObjectMeta = src.ObjectMeta
+	in.Spec = *(*WorkspaceSpec)(unsafe.Pointer(&src.Spec))
+	in.Status = *(*WorkspaceStatus)(unsafe.Pointer(&src.Status))
+
+	return nil
+}
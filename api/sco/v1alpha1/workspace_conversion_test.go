@@ -0,0 +1,148 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	fuzz "github.com/google/gofuzz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+)
+
+const fuzzIterations = 1000
+
+func fuzzer(seed int64) *fuzz.Fuzzer {
+	return withWorkspaceFuncs(fuzz.NewWithSeed(seed))
+}
+
+func withWorkspaceFuncs(f *fuzz.Fuzzer) *fuzz.Fuzzer {
+	return f.
+		NilChance(0.2).
+		NumElements(0, 3).
+		Funcs(
+			// the time has no exported fields, hence it is not fuzzed otherwise
+			func(t *metav1.Time, c fuzz.Continue) {
+				*t = metav1.Unix(c.Int63n(1<<32), 0)
+			},
+			// the traits are JSON objects
+			func(r *runtime.RawExtension, c fuzz.Continue) {
+				r.Raw = []byte(fmt.Sprintf(`{"%s":%d}`, c.RandString(), c.Int()))
+			},
+			// the apiVersion and kind are set by the conversion machinery
+			func(t *metav1.TypeMeta, _ fuzz.Continue) {},
+		)
+}
+
+func TestWorkspaceConversionRoundTrip(t *testing.T) {
+	f := fuzzer(1)
+
+	for i := 0; i < fuzzIterations; i++ {
+		in := Workspace{}
+		f.Fuzz(&in)
+
+		hub := v1beta1.Workspace{}
+		require.NoError(t, in.ConvertTo(&hub))
+
+		out := Workspace{}
+		require.NoError(t, out.ConvertFrom(&hub))
+
+		require.Equal(t, in, out)
+	}
+}
+
+func TestWorkspaceConversionHubRoundTrip(t *testing.T) {
+	f := fuzzer(2)
+
+	for i := 0; i < fuzzIterations; i++ {
+		in := v1beta1.Workspace{}
+		f.Fuzz(&in)
+
+		spoke := Workspace{}
+		require.NoError(t, spoke.ConvertFrom(&in))
+
+		out := v1beta1.Workspace{}
+		require.NoError(t, spoke.ConvertTo(&out))
+
+		require.Equal(t, in, out)
+	}
+}
+
+func TestWorkspaceConversionDoesNotAlias(t *testing.T) {
+	in := Workspace{}
+	fuzzer(3).NilChance(0).NumElements(1, 1).Fuzz(&in)
+
+	hub := v1beta1.Workspace{}
+	require.NoError(t, in.ConvertTo(&hub))
+
+	hub.Spec.Platform.Build.Registry.Address = "changed"
+	hub.Spec.Profiles[0].Kamelet.Repositories[0] = "changed"
+	hub.Status.Conditions[0].Reason = "Changed"
+	hub.Labels["changed"] = "true"
+
+	assert.NotEqual(t, "changed", in.Spec.Platform.Build.Registry.Address)
+	assert.NotEqual(t, "changed", in.Spec.Profiles[0].Kamelet.Repositories[0])
+	assert.NotEqual(t, "Changed", in.Status.Conditions[0].Reason)
+	assert.NotContains(t, in.Labels, "changed")
+}
+
+// TestWorkspaceSchema checks that the types of both versions have the same fields,
+// with the same names and the same kinds, as required by the conversion.
+func TestWorkspaceSchema(t *testing.T) {
+	assertSameSchema(t, reflect.TypeOf(Workspace{}), reflect.TypeOf(v1beta1.Workspace{}), "Workspace")
+}
+
+func assertSameSchema(t *testing.T, spoke reflect.Type, hub reflect.Type, path string) {
+	t.Helper()
+
+	require.Equal(t, hub.Kind(), spoke.Kind(), path)
+	require.Equal(t, hub.Size(), spoke.Size(), path)
+
+	switch spoke.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		assertSameSchema(t, spoke.Elem(), hub.Elem(), path+"[]")
+	case reflect.Map:
+		assertSameSchema(t, spoke.Key(), hub.Key(), path+"[key]")
+		assertSameSchema(t, spoke.Elem(), hub.Elem(), path+"[]")
+	case reflect.Struct:
+		// the types shared by both versions, e.g. ObjectMeta
+		if spoke == hub {
+			return
+		}
+
+		require.Equal(t, hub.NumField(), spoke.NumField(), path)
+
+		for i := 0; i < spoke.NumField(); i++ {
+			s, h := spoke.Field(i), hub.Field(i)
+
+			require.Equal(t, h.Name, s.Name, path)
+			require.Equal(t, h.Tag, s.Tag, path+"."+s.Name)
+			require.Equal(t, h.Offset, s.Offset, path+"."+s.Name)
+
+			assertSameSchema(t, s.Type, h.Type, path+"."+s.Name)
+		}
+	}
+}
+
+// FuzzWorkspaceConversion checks that the conversion to the hub version and back is
+// lossless for arbitrary Workspaces, run it with go test -fuzz.
+func FuzzWorkspaceConversion(f *testing.F) {
+	f.Add([]byte("workspace"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := Workspace{}
+		withWorkspaceFuncs(fuzz.NewFromGoFuzz(data)).Fuzz(&in)
+
+		hub := v1beta1.Workspace{}
+		require.NoError(t, in.ConvertTo(&hub))
+
+		out := Workspace{}
+		require.NoError(t, out.ConvertFrom(&hub))
+
+		require.Equal(t, in, out)
+	})
+}
@@ -18,22 +18,328 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type WorkspaceSpec struct {
+	// Platform holds the settings of the Camel K IntegrationPlatform managed by the Workspace.
+	// +optional
+	Platform *PlatformSpec `json:"platform,omitempty"`
+
+	// Profiles holds the Camel K IntegrationProfiles managed by the Workspace, in addition
+	// to the IntegrationPlatform. They require a Camel K release serving IntegrationProfiles.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Profiles []ProfileSpec `json:"profiles,omitempty"`
+
+	// Expose makes the HTTP entry point of the Workspace reachable from outside of the
+	// cluster, through a Route, an Ingress or a Gateway API HTTPRoute.
+	// +optional
+	Expose *ExposeSpec `json:"expose,omitempty"`
+
+	// DeletionPolicy defines what happens to the resources owned by the Workspace
+	// when the Workspace is deleted.
+	// +kubebuilder:default=Delete
+	// +optional
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// Paused stops the reconciliation of the Workspace until it is set back to false.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete lets the owned resources be garbage collected along with the Workspace.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyOrphan removes the owner references from the owned resources but keeps
+	// the operator labels, so they can be adopted by a new Workspace.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+	// DeletionPolicyRetain removes both the owner references and the operator labels from
+	// the owned resources, handing them over completely.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+type PlatformSpec struct {
+	// Build holds the settings used to build integrations.
+	// +optional
+	Build *BuildSpec `json:"build,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=routine;pod
+type BuildStrategy string
+
+const (
+	BuildStrategyRoutine BuildStrategy = "routine"
+	BuildStrategyPod     BuildStrategy = "pod"
+)
+
+// PublishStrategy is the strategy used to publish integration images. Only the
+// strategies supported by Camel K 2.x are accepted, Jib is not supported by 1.x.
+// +kubebuilder:validation:Enum=S2I;Spectrum;Jib
+type PublishStrategy string
+
+const (
+	PublishStrategyS2I      PublishStrategy = "S2I"
+	PublishStrategySpectrum PublishStrategy = "Spectrum"
+	PublishStrategyJib      PublishStrategy = "Jib"
+)
+
+type BuildSpec struct {
+	// Strategy is the strategy used to run builds, defaults to the one configured
+	// for the operator, if any, or to the Camel K one otherwise.
+	// +optional
+	Strategy BuildStrategy `json:"strategy,omitempty"`
+
+	// PublishStrategy is the strategy used to publish integration images.
+	// +optional
+	PublishStrategy PublishStrategy `json:"publishStrategy,omitempty"`
+
+	// Registry is the container registry integration images are pushed to.
+	// +optional
+	Registry *RegistrySpec `json:"registry,omitempty"`
+
+	// BaseImage is the image used as base layer for all integration images.
+	// +optional
+	BaseImage string `json:"baseImage,omitempty"`
+
+	// Timeout is how long a build may run before being cancelled.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// RuntimeVersion is the Camel K runtime version used by integrations.
+	// +optional
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+}
+
+type RegistrySpec struct {
+	// Address is the address of the registry.
+	Address string `json:"address"`
+
+	// Secret is the name of the secret holding the registry credentials.
+	// +optional
+	Secret string `json:"secret,omitempty"`
+
+	// Insecure allows to push to a registry without TLS.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+type ProfileSpec struct {
+	// Name is the name of the IntegrationProfile.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Traits holds the default configuration of the traits of the integrations using
+	// the profile, keyed by trait name.
+	// +optional
+	Traits map[string]runtime.RawExtension `json:"traits,omitempty"`
+
+	// Build holds the settings used to build the integrations using the profile.
+	// +optional
+	Build *ProfileBuildSpec `json:"build,omitempty"`
+
+	// Kamelet holds the settings used to load Kamelets.
+	// +optional
+	Kamelet *KameletSpec `json:"kamelet,omitempty"`
+}
+
+type ProfileBuildSpec struct {
+	// Registry is the container registry integration images are pushed to.
+	// +optional
+	Registry *RegistrySpec `json:"registry,omitempty"`
+
+	// BaseImage is the image used as base layer for all integration images.
+	// +optional
+	BaseImage string `json:"baseImage,omitempty"`
+
+	// Timeout is how long a build may run before being cancelled.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// RuntimeVersion is the Camel K runtime version used by integrations.
+	// +optional
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
 }
 
+type KameletSpec struct {
+	// Repositories are the URIs of the repositories Kamelets are loaded from,
+	// e.g. github:apache/camel-kamelets/kamelets.
+	// +optional
+	Repositories []string `json:"repositories,omitempty"`
+}
+
+// Passthrough routes forward the TLS connection as is, they cannot match on a path.
+// +kubebuilder:validation:XValidation:rule="!has(self.tls) || !has(self.tls.termination) || self.tls.termination != 'passthrough' || !has(self.path) || self.path == '/'",message="path is not supported with the passthrough termination"
+type ExposeSpec struct {
+	// Mode is the API used to expose the entry point. When empty, it is set at admission
+	// time to the mode implied by the gateway or the ingressClassName fields, else to the
+	// default mode of the operator, else to Route on OpenShift, then Ingress if served,
+	// then Gateway if the Gateway API is the only exposure API served by the cluster.
+	// +optional
+	Mode ExposeMode `json:"mode,omitempty"`
+
+	// Service is the name of the Service backing the HTTP entry point of the Workspace.
+	Service string `json:"service"`
+
+	// Port is the name or the number of the Service port.
+	// +kubebuilder:default=http
+	// +optional
+	Port intstr.IntOrString `json:"port,omitempty"`
+
+	// Host is a template of the host name, rendered with the name and the namespace of
+	// the Workspace, e.g. {{ .Name }}-{{ .Namespace }}.apps.example.com. When empty, the
+	// host is assigned by the router on OpenShift or taken from the Ingress load balancer.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Path is the path the entry point is exposed at.
+	// +kubebuilder:default=/
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// IngressClassName is the class of the Ingress, only used by the Ingress mode.
+	// +optional
+	IngressClassName string `json:"ingressClassName,omitempty"`
+
+	// Gateway is the Gateway HTTPRoutes are attached to, required by the Gateway mode.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+
+	// TLS enables TLS for the entry point. In the Gateway mode, TLS is configured by
+	// the listeners of the Gateway instead.
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Route;Ingress;Gateway
+type ExposeMode string
+
+const (
+	// ExposeModeRoute exposes the entry point through an OpenShift Route.
+	ExposeModeRoute ExposeMode = "Route"
+	// ExposeModeIngress exposes the entry point through an Ingress.
+	ExposeModeIngress ExposeMode = "Ingress"
+	// ExposeModeGateway exposes the entry point through a Gateway API HTTPRoute.
+	ExposeModeGateway ExposeMode = "Gateway"
+)
+
+type GatewayReference struct {
+	// Name is the name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Gateway, defaults to the namespace of the Workspace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the name of the listener of the Gateway to attach to, all the
+	// listeners when empty.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=edge;passthrough;reencrypt
+type TLSTermination string
+
+const (
+	TLSTerminationEdge        TLSTermination = "edge"
+	TLSTerminationPassthrough TLSTermination = "passthrough"
+	TLSTerminationReencrypt   TLSTermination = "reencrypt"
+)
+
+type TLSSpec struct {
+	// SecretName is the name of the Secret holding the certificate of an Ingress. It is
+	// ignored on OpenShift, where the certificate of the router is used.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Termination is where TLS is terminated, only supported by Routes.
+	// +kubebuilder:default=edge
+	// +optional
+	Termination TLSTermination `json:"termination,omitempty"`
+
+	// InsecureRedirect redirects plain HTTP requests to HTTPS, only supported by Routes.
+	// +optional
+	InsecureRedirect bool `json:"insecureRedirect,omitempty"`
+}
+
+// WorkspacePhase is a label for the condition of a Workspace at the current time.
+// +kubebuilder:validation:Enum=Pending;Provisioning;Ready;Degraded;Paused;Deleting;Error
+type WorkspacePhase string
+
+const (
+	// WorkspacePhasePending means the Workspace has been accepted but its IntegrationPlatform
+	// has not been picked up by Camel K yet.
+	WorkspacePhasePending WorkspacePhase = "Pending"
+	// WorkspacePhaseProvisioning means all the resources have been applied and Camel K
+	// is bringing the IntegrationPlatform up.
+	WorkspacePhaseProvisioning WorkspacePhase = "Provisioning"
+	// WorkspacePhaseReady means the IntegrationPlatform is ready.
+	WorkspacePhaseReady WorkspacePhase = "Ready"
+	// WorkspacePhaseDegraded means the Workspace has been Ready but the IntegrationPlatform
+	// is not ready anymore.
+	WorkspacePhaseDegraded WorkspacePhase = "Degraded"
+	// WorkspacePhasePaused means the reconciliation of the Workspace is paused.
+	WorkspacePhasePaused WorkspacePhase = "Paused"
+	// WorkspacePhaseDeleting means the Workspace is being deleted and its resources cleaned up.
+	WorkspacePhaseDeleting WorkspacePhase = "Deleting"
+	// WorkspacePhaseError means the reconciliation has failed or the IntegrationPlatform is in error.
+	WorkspacePhaseError WorkspacePhase = "Error"
+)
+
 type WorkspaceStatus struct {
-	Phase              string             `json:"phase"`
+	Phase WorkspacePhase `json:"phase"`
+	// LastTransitionTime is the last time the phase transitioned from one value to another.
+	LastTransitionTime *metav1.Time       `json:"lastTransitionTime,omitempty"`
 	Conditions         []metav1.Condition `json:"conditions,omitempty"`
 	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
-	Endpoint           string             `json:"endpoint,omitempty"`
+	// Endpoint is the URL the HTTP entry point of the Workspace is exposed at.
+	Endpoint string              `json:"endpoint,omitempty"`
+	Orphaned []ResourceReference `json:"orphaned,omitempty"`
+	// CamelK reports the Camel K installation detected in the cluster.
+	CamelK *CamelKStatus `json:"camelK,omitempty"`
+	// Profiles reports the status of the IntegrationProfiles managed by the Workspace.
+	// +listType=map
+	// +listMapKey=name
+	Profiles []ProfileStatus `json:"profiles,omitempty"`
+}
+
+type ProfileStatus struct {
+	// Name is the name of the IntegrationProfile.
+	Name       string             `json:"name"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type CamelKStatus struct {
+	// Version is the Camel K major version inferred from the APIs served by the cluster,
+	// either 1.x or 2.x.
+	Version string `json:"version"`
+
+	// OperatorVersion is the version of the Camel K operator which reconciled the
+	// IntegrationPlatform, when known.
+	// +optional
+	OperatorVersion string `json:"operatorVersion,omitempty"`
+}
+
+type ResourceReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
 }
 
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The phase"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="The Ready condition"
+// +kubebuilder:printcolumn:name="Camel K",type=string,JSONPath=`.status.camelK.version`,description="The detected Camel K version",priority=1
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.status.endpoint`,description="The URL of the HTTP entry point",priority=1
 // +kubebuilder:resource:path=workspaces,scope=Namespaced,shortName=ws,categories=integration;camel
 
 type Workspace struct {
@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "k8s.io/apimachinery/pkg/runtime/schema"
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+)
 
 func init() {
 	SchemeBuilder.Register(&Workspace{}, &WorkspaceList{})
@@ -10,3 +14,13 @@ func init() {
 func Resource(resource string) schema.GroupResource {
 	return SchemeGroupVersion.WithResource(resource).GroupResource()
 }
+
+// GetConditions returns the list of conditions of the Workspace.
+func (in *Workspace) GetConditions() conditions.Conditions {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the list of conditions of the Workspace.
+func (in *Workspace) SetConditions(conditions conditions.Conditions) {
+	in.Status.Conditions = conditions
+}
@@ -0,0 +1,5 @@
+package v1beta1
+
+// Hub marks this version as the conversion hub, all the other versions of the
+// Workspace are converted to and from it.
+func (*Workspace) Hub() {}
@@ -0,0 +1,26 @@
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+)
+
+func init() {
+	SchemeBuilder.Register(&Workspace{}, &WorkspaceList{})
+}
+
+// Resource takes an unqualified resource and returns a Group qualified GroupResource.
+func Resource(resource string) schema.GroupResource {
+	return SchemeGroupVersion.WithResource(resource).GroupResource()
+}
+
+// GetConditions returns the list of conditions of the Workspace.
+func (in *Workspace) GetConditions() conditions.Conditions {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the list of conditions of the Workspace.
+func (in *Workspace) SetConditions(conditions conditions.Conditions) {
+	in.Status.Conditions = conditions
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	"github.com/sco1237896/sco-operator/cmd/migrate"
 	"github.com/sco1237896/sco-operator/cmd/run"
 	"github.com/sco1237896/sco-operator/pkg/logger"
 )
@@ -36,6 +37,7 @@ func main() {
 	}
 
 	rootCmd.AddCommand(run.NewRunCmd())
+	rootCmd.AddCommand(migrate.NewMigrateCmd())
 
 	fs := flag.NewFlagSet("", flag.PanicOnError)
 
@@ -0,0 +1,51 @@
+package migrate
+
+import (
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/sco1237896/sco-operator/pkg/logger"
+	"github.com/sco1237896/sco-operator/pkg/migration"
+
+	wsCtl "github.com/sco1237896/sco-operator/internal/controller/sco"
+)
+
+func NewMigrateCmd() *cobra.Command {
+	pageSize := int64(migration.DefaultPageSize)
+
+	cmd := cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the stored Workspaces to the storage version",
+		Long: `Migrate the stored Workspaces to the storage version, then trim the stored versions
+of the Workspace CRD down to the storage version.
+
+The Workspaces are rewritten as they are, through the admission webhooks. Unless run
+as the trusted user of the webhooks (see the webhook-trusted-user flag of the run
+command), the legacy Workspaces that are not valid anymore are rejected and have to
+be repaired manually. They are reported and keep the stored versions from being
+trimmed until the migration succeeds.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctrl.SetLogger(logger.New(&logger.Options))
+
+			cfg, err := ctrl.GetConfig()
+			if err != nil {
+				return err
+			}
+
+			m, err := migration.New(cfg, ctrl.Log.WithName("migration"))
+			if err != nil {
+				return err
+			}
+
+			m.PageSize = pageSize
+
+			_, err = m.Migrate(ctrl.SetupSignalHandler(), wsCtl.WorkspaceCRDName)
+
+			return err
+		},
+	}
+
+	cmd.Flags().Int64Var(&pageSize, "page-size", pageSize, "The number of Workspaces listed at once.")
+
+	return &cmd
+}
@@ -0,0 +1,98 @@
+package run
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/config"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+	"github.com/sco1237896/sco-operator/pkg/logger"
+)
+
+// configure returns the given options updated with the values of the configuration
+// file, the flags explicitly set on the command line take precedence.
+func configure(cmd *cobra.Command, c config.Configuration, o controller.Options) controller.Options {
+	changed := func(names ...string) bool {
+		for _, name := range names {
+			if f := cmd.Flag(name); f != nil && f.Changed {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if c.WorkspaceDefaults.Registry != nil && !changed("default-registry", "default-registry-secret", "default-registry-insecure") {
+		o.Defaults.Registry = c.WorkspaceDefaults.Registry.DeepCopy()
+	}
+
+	o.Defaults.BuildStrategy = pick(changed("default-build-strategy"), o.Defaults.BuildStrategy, c.WorkspaceDefaults.BuildStrategy)
+	o.Defaults.RuntimeVersion = pick(changed("default-runtime-version"), o.Defaults.RuntimeVersion, c.WorkspaceDefaults.RuntimeVersion)
+	o.Defaults.ExposeMode = pick(changed("default-expose-mode"), o.Defaults.ExposeMode, c.WorkspaceDefaults.ExposeMode)
+
+	o.MaxConcurrentReconciles = pick(changed("max-concurrent-reconciles"), o.MaxConcurrentReconciles, c.Controller.MaxConcurrentReconciles)
+	o.SyncPeriod = pick(changed("sync-period"), o.SyncPeriod, config.Duration(c.Controller.SyncPeriod))
+	o.CapabilitiesRefreshInterval = pick(changed("capabilities-refresh-interval"), o.CapabilitiesRefreshInterval, config.Duration(c.Controller.CapabilitiesRefreshInterval))
+	o.EventDeduplicationInterval = pick(changed("event-deduplication-interval"), o.EventDeduplicationInterval, config.Duration(c.Controller.EventDeduplicationInterval))
+
+	if !changed("storage-migration") {
+		o.EnableStorageMigration = c.FeatureEnabled(config.FeatureStorageMigration)
+	}
+
+	return o
+}
+
+// configureLogging applies the logging configuration to the given options, the
+// flags explicitly set on the command line take precedence. It applies to the
+// loggers already created.
+func configureLogging(cmd *cobra.Command, c config.Configuration, o *logger.LogOptions) error {
+	if f := cmd.Flag("zap-log-level"); c.Logging.Level != "" && (f == nil || !f.Changed) {
+		if err := o.SetLevel(c.Logging.Level); err != nil {
+			return err
+		}
+	}
+
+	if f := cmd.Flag("log-override"); f == nil || !f.Changed {
+		o.SetOverrides(c.LogOverrides())
+	}
+
+	return nil
+}
+
+// reload returns the listener applying the configuration changes that do not
+// require a restart: the Workspace defaults, the capabilities refresh interval,
+// the log level and the logger overrides. The options are the ones set through
+// flags, which take precedence: they are copied and never written to, the changes
+// are only published through the provider, the capabilities service and the
+// loggers, which are safe for concurrent use.
+func reload(
+	cmd *cobra.Command,
+	options controller.Options,
+	provider *defaults.Provider,
+	caps *capabilities.Service,
+	o *logger.LogOptions,
+) func(config.Configuration) {
+	return func(c config.Configuration) {
+		opts := configure(cmd, c, options)
+
+		provider.Set(opts.Defaults)
+		caps.SetRefreshInterval(opts.CapabilitiesRefreshInterval)
+
+		if err := configureLogging(cmd, c, o); err != nil {
+			controller.Log.Error(err, "unable to apply the logging configuration")
+		}
+	}
+}
+
+// pick returns the value of the flag if it has been set explicitly or if the
+// configuration file does not set a value.
+func pick[T comparable](changed bool, flag T, file T) T {
+	var zero T
+
+	if changed || file == zero {
+		return flag
+	}
+
+	return file
+}
@@ -0,0 +1,125 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/config"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+	"github.com/sco1237896/sco-operator/pkg/logger"
+)
+
+const initial = `
+apiVersion: config.sco1237896.github.com/v1alpha1
+kind: OperatorConfiguration
+workspaceDefaults:
+  runtimeVersion: 3.0.0
+`
+
+const updated = `
+apiVersion: config.sco1237896.github.com/v1alpha1
+kind: OperatorConfiguration
+workspaceDefaults:
+  buildStrategy: pod
+  runtimeVersion: 3.2.0
+controller:
+  capabilitiesRefreshInterval: 1m
+logging:
+  overrides:
+    controller: debug
+`
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(initial), 0o600))
+
+	cmd := NewRunCmd()
+	require.NoError(t, cmd.Flags().Set("default-runtime-version", "3.1.0"))
+
+	options := controller.Options{
+		Defaults: defaults.Workspace{RuntimeVersion: "3.1.0"},
+	}
+
+	provider := defaults.NewProvider(options.Defaults)
+	caps := capabilities.NewService(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}})
+
+	out := bytes.Buffer{}
+	o := logger.LogOptions{
+		Options: ctrlzap.Options{
+			DestWriter: &out,
+		},
+	}
+
+	l := logger.New(&o).WithName("controller")
+
+	store := config.NewStore(path)
+
+	_, err := store.Load()
+	require.NoError(t, err)
+
+	store.OnChange(reload(cmd, options, provider, caps, &o))
+
+	// the defaults and the loggers are used while the configuration is reloaded,
+	// run with -race to check they are not shared with the reload
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				provider.Get().Apply(&v1beta1.Workspace{}, caps.Get())
+				l.WithName("workspace").V(2).Info("reloading")
+			}
+		}
+	}()
+
+	require.NoError(t, os.WriteFile(path, []byte(updated), 0o600))
+
+	changed, err := store.Reload(context.Background())
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	close(done)
+	<-stopped
+
+	// the flags set explicitly take precedence
+	assert.Equal(t, "3.1.0", provider.Get().RuntimeVersion)
+	assert.Equal(t, v1beta1.BuildStrategyPod, provider.Get().BuildStrategy)
+
+	assert.Equal(t, time.Minute, caps.RefreshInterval())
+
+	// the overrides apply to the loggers already created
+	l.V(1).Info("visible")
+	assert.Contains(t, out.String(), "visible")
+
+	// the settings that are removed are reset
+	require.NoError(t, os.WriteFile(path, []byte(initial), 0o600))
+
+	changed, err = store.Reload(context.Background())
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	assert.Equal(t, capabilities.DefaultRefreshInterval, caps.RefreshInterval())
+
+	out.Reset()
+	l.V(1).Info("hidden")
+	assert.Empty(t, out.String())
+}
@@ -2,21 +2,31 @@ package run
 
 import (
 	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	routev1 "github.com/openshift/api/route/v1"
 
+	"github.com/sco1237896/sco-operator/pkg/config"
 	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+	"github.com/sco1237896/sco-operator/pkg/logger"
+	"github.com/sco1237896/sco-operator/pkg/migration"
+	"github.com/sco1237896/sco-operator/pkg/tracing"
 	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
-	wsApi "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	wsApiV1alpha1 "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
 	wsCtl "github.com/sco1237896/sco-operator/internal/controller/sco"
+	wsWh "github.com/sco1237896/sco-operator/internal/webhook/sco"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 )
 
 func init() {
+	utilruntime.Must(wsApiV1alpha1.AddToScheme(controller.Scheme))
 	utilruntime.Must(wsApi.AddToScheme(controller.Scheme))
 	utilruntime.Must(camelv1.AddToScheme(controller.Scheme))
-	// utilruntime.Must(routev1.Install(controller.Scheme))
+	utilruntime.Must(routev1.Install(controller.Scheme))
 }
 
 func NewRunCmd() *cobra.Command {
@@ -29,23 +39,94 @@ func NewRunCmd() *cobra.Command {
 		EnableLeaderElection:          true,
 		ReleaseLeaderElectionOnCancel: true,
 		LeaderElectionNamespace:       "",
+		EnableAPIClientLogging:        false,
+		EnableWebhooks:                false,
+		WebhookPort:                   9443,
+		WebhookCertDir:                "",
+		WebhookTrustedUser:            "",
+		EnableStorageMigration:        true,
+		Tracing: tracing.Options{
+			Exporter: tracing.ExporterNone,
+			Endpoint: "http://localhost:4318",
+			File:     "",
+		},
 	}
 
+	var registry wsApi.RegistrySpec
+
 	cmd := cobra.Command{
 		Use:   "run",
 		Short: "run",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return controller.Start(options, func(manager manager.Manager, opts controller.Options) error {
-				rec, err := wsCtl.NewKWorkspaceReconciler(manager)
+			if registry.Address != "" {
+				options.Defaults.Registry = &registry
+			}
+
+			var store *config.Store
+
+			opts := options
+
+			if options.ConfigFile != "" {
+				store = config.NewStore(options.ConfigFile, config.WithLogger(ctrl.Log.WithName("config")))
+
+				c, err := store.Load()
 				if err != nil {
 					return err
 				}
 
-				return rec.SetupWithManager(cmd.Context(), manager)
+				opts = configure(cmd, c, options)
+
+				if err := configureLogging(cmd, c, &logger.Options); err != nil {
+					return err
+				}
+			}
+
+			if err := opts.Defaults.Validate(); err != nil {
+				return err
+			}
+
+			provider := defaults.NewProvider(opts.Defaults)
+
+			return controller.Start(opts, func(manager manager.Manager, opts controller.Options) error {
+				rec, err := wsCtl.NewKWorkspaceReconciler(manager, opts, provider)
+				if err != nil {
+					return err
+				}
+
+				if store != nil {
+					store.OnChange(reload(cmd, options, provider, rec.Capabilities, &logger.Options))
+
+					if err := manager.Add(store); err != nil {
+						return err
+					}
+				}
+
+				if err := rec.SetupWithManager(cmd.Context(), manager); err != nil {
+					return err
+				}
+
+				if opts.EnableStorageMigration {
+					m, err := migration.New(manager.GetConfig(), ctrl.Log.WithName("migration"))
+					if err != nil {
+						return err
+					}
+
+					if err := manager.Add(m.Runnable(wsCtl.WorkspaceCRDName)); err != nil {
+						return err
+					}
+				}
+
+				if opts.EnableWebhooks {
+					return wsWh.SetupWorkspaceWebhookWithManager(manager, provider, rec.Capabilities, opts.WebhookTrustedUser)
+				}
+
+				return nil
 			})
 		},
 	}
 
+	cmd.Flags().StringVar(&options.ConfigFile, "config", options.ConfigFile, "The configuration file of the operator, reloaded on change. The flags set explicitly take precedence.")
+
 	cmd.Flags().StringVar(&options.LeaderElectionID, "leader-election-id", options.LeaderElectionID, "The leader election ID of the operator.")
 	cmd.Flags().StringVar(&options.LeaderElectionNamespace, "leader-election-namespace", options.LeaderElectionNamespace, "The leader election namespace.")
 	cmd.Flags().BoolVar(&options.EnableLeaderElection, "leader-election", options.EnableLeaderElection, "Enable leader election for controller manager.")
@@ -55,5 +136,30 @@ func NewRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&options.ProbeAddr, "health-probe-bind-address", options.ProbeAddr, "The address the probe endpoint binds to.")
 	cmd.Flags().StringVar(&options.PprofAddr, "pprof-bind-address", options.PprofAddr, "The address the pprof endpoint binds to.")
 
+	cmd.Flags().StringVar(&options.Tracing.Exporter, "tracing-exporter", options.Tracing.Exporter, "The tracing exporter, one of none, otlp, stdout or file.")
+	cmd.Flags().StringVar(&options.Tracing.Endpoint, "tracing-endpoint", options.Tracing.Endpoint, "The base URL of the OTLP/HTTP collector traces are exported to.")
+	cmd.Flags().StringVar(&options.Tracing.File, "tracing-file", options.Tracing.File, "The file traces are written to when using the file exporter.")
+
+	cmd.Flags().BoolVar(&options.EnableWebhooks, "webhooks", options.EnableWebhooks, "Enable the admission webhooks.")
+	cmd.Flags().IntVar(&options.WebhookPort, "webhook-port", options.WebhookPort, "The port the webhook server binds to.")
+	cmd.Flags().StringVar(&options.WebhookCertDir, "webhook-cert-dir", options.WebhookCertDir, "The directory holding the serving certificate of the webhook server, defaults to <temp-dir>/k8s-webhook-server/serving-certs.")
+	cmd.Flags().StringVar(&options.WebhookTrustedUser, "webhook-trusted-user", options.WebhookTrustedUser, "The user whose updates leaving the spec unchanged are neither defaulted nor validated by the webhooks, meant to be the service account of the operator, e.g. system:serviceaccount:<namespace>:<name>.")
+
+	cmd.Flags().IntVar(&options.MaxConcurrentReconciles, "max-concurrent-reconciles", options.MaxConcurrentReconciles, "The number of Workspaces reconciled at once, defaults to 1.")
+	cmd.Flags().DurationVar(&options.SyncPeriod, "sync-period", options.SyncPeriod, "How often the cached objects are reconciled again, defaults to 10 hours.")
+	cmd.Flags().DurationVar(&options.CapabilitiesRefreshInterval, "capabilities-refresh-interval", options.CapabilitiesRefreshInterval, "How often the capabilities of the cluster are discovered again, defaults to 5 minutes.")
+	cmd.Flags().DurationVar(&options.EventDeduplicationInterval, "event-deduplication-interval", options.EventDeduplicationInterval, "How long identical events are suppressed, defaults to 5 minutes.")
+
+	cmd.Flags().BoolVar(&options.EnableStorageMigration, "storage-migration", options.EnableStorageMigration, "Migrate the stored Workspaces to the storage version at startup.")
+
+	cmd.Flags().StringVar(&registry.Address, "default-registry", registry.Address, "The registry the images are published to, unless set by the Workspace.")
+	cmd.Flags().StringVar(&registry.Secret, "default-registry-secret", registry.Secret, "The secret holding the credentials of the default registry.")
+	cmd.Flags().BoolVar(&registry.Insecure, "default-registry-insecure", registry.Insecure, "If the default registry is insecure.")
+	cmd.Flags().StringVar((*string)(&options.Defaults.BuildStrategy), "default-build-strategy", string(options.Defaults.BuildStrategy), "The build strategy, unless set by the Workspace, one of routine or pod. Camel K picks one when empty.")
+	cmd.Flags().StringVar(&options.Defaults.RuntimeVersion, "default-runtime-version", options.Defaults.RuntimeVersion, "The Camel K runtime version, unless set by the Workspace.")
+	cmd.Flags().StringVar((*string)(&options.Defaults.ExposeMode), "default-expose-mode", string(options.Defaults.ExposeMode), "The preferred exposure mode, one of Route, Ingress or Gateway, defaults to the first one served by the cluster.")
+
+	cmd.Flags().BoolVar(&options.EnableAPIClientLogging, "api-client-logging", options.EnableAPIClientLogging, "Log the requests and responses of failing API calls.")
+
 	return &cmd
 }
@@ -0,0 +1,13 @@
+#!/bin/sh
+
+set -e
+
+
+kubectl apply -f https://github.com/cert-manager/cert-manager/releases/download/v1.13.2/cert-manager.yaml
+
+kubectl wait \
+  --namespace=cert-manager \
+  --for=condition=ready \
+  pod \
+  --selector=app.kubernetes.io/instance=cert-manager \
+  --timeout=90s
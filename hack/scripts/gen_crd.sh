@@ -9,4 +9,7 @@ PROJECT_ROOT="$1"
 "${PROJECT_ROOT}"/bin/controller-gen \
   rbac:roleName=sco-operator-role \
   crd \
-  paths="./..." output:crd:artifacts:config="${PROJECT_ROOT}/config/crd/bases"
+  webhook \
+  paths="./..." \
+  output:crd:artifacts:config="${PROJECT_ROOT}/config/crd/bases" \
+  output:webhook:artifacts:config="${PROJECT_ROOT}/config/webhook"
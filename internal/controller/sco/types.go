@@ -1,6 +1,39 @@
 package sco
 
+import (
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+)
+
 const (
 	ApplicationName        = "sco-operator"
 	OperatorName    string = "sco-operator"
+
+	ConditionTypeReady         = "Ready"
+	ConditionTypeReconcile     = "Reconcile"
+	ConditionTypeCleanup       = "Cleanup"
+	ConditionTypePaused        = "Paused"
+	ConditionTypeDeployment    = "Deployment"
+	ConditionTypePlatformReady = "PlatformReady"
+	ConditionTypeProfiles      = "Profiles"
+	ConditionTypeExposed       = "Exposed"
+
+	// ConditionTypeAvailableSuffix is appended to the name of a capability to
+	// get the type of the condition reporting its availability, e.g. CamelKAvailable.
+	ConditionTypeAvailableSuffix = "Available"
+
+	// ReasonCapabilityMissing is the reason of the conditions which can't be
+	// evaluated as the capabilities they depend on are missing.
+	ReasonCapabilityMissing = "CapabilityMissing"
+
+	// EventReasonPhaseChanged is the reason of the events emitted on phase transitions.
+	EventReasonPhaseChanged = "PhaseChanged"
 )
+
+// WorkspaceCRDName is the name of the CustomResourceDefinition of the Workspaces.
+var WorkspaceCRDName = wsApi.GroupVersion.WithResource("workspaces").GroupResource().String()
+
+func capabilityConditionType(c capabilities.Capability) conditions.ConditionType {
+	return conditions.ConditionType(string(c) + ConditionTypeAvailableSuffix)
+}
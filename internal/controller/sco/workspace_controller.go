@@ -18,48 +18,92 @@ package sco
 
 import (
 	"context"
-	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	wsApi "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	"github.com/rs/xid"
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
 	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
 	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/controller/predicates"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+	"github.com/sco1237896/sco-operator/pkg/events"
+	"github.com/sco1237896/sco-operator/pkg/metrics"
+	"github.com/sco1237896/sco-operator/pkg/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/go-logr/logr"
 	client "github.com/sco1237896/sco-operator/pkg/controller/client"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func NewKWorkspaceReconciler(manager ctrl.Manager) (*WorkspaceReconciler, error) {
+func NewKWorkspaceReconciler(manager ctrl.Manager, opts controller.Options, d *defaults.Provider) (*WorkspaceReconciler, error) {
 	c, err := client.NewClient(manager.GetConfig(), manager.GetScheme(), manager.GetClient())
 	if err != nil {
 		return nil, err
 	}
 
-	rec := WorkspaceReconciler{
-		Client:      c,
-		Scheme:      manager.GetScheme(),
-		ClusterType: controller.ClusterTypeVanilla,
-		actions:     make([]controller.Action[wsApi.Workspace], 0),
-		l:           ctrl.Log.WithName("controller"),
+	capsOpts := []capabilities.Option{capabilities.WithLogger(ctrl.Log.WithName("capabilities"))}
+	if opts.CapabilitiesRefreshInterval > 0 {
+		capsOpts = append(capsOpts, capabilities.WithRefreshInterval(opts.CapabilitiesRefreshInterval))
 	}
-	rec.actions = append(rec.actions, NewDeployAction(rec.l))
 
-	isOpenshift, err := c.IsOpenShift()
-	if err != nil {
+	// transitions may legitimately repeat, e.g. Ready -> Degraded -> Ready
+	eventsOpts := []events.Option{events.WithoutDeduplication(EventReasonPhaseChanged)}
+	if opts.EventDeduplicationInterval > 0 {
+		eventsOpts = append(eventsOpts, events.WithDeduplicationInterval(opts.EventDeduplicationInterval))
+	}
+
+	caps := capabilities.NewService(c.Discovery, capsOpts...)
+	if _, err := caps.Refresh(context.Background()); err != nil {
 		return nil, err
 	}
-	if isOpenshift {
-		rec.ClusterType = controller.ClusterTypeOpenShift
+
+	// refresh the capabilities periodically
+	if err := manager.Add(caps); err != nil {
+		return nil, err
+	}
+
+	rec := WorkspaceReconciler{
+		Client:       c,
+		Scheme:       manager.GetScheme(),
+		Capabilities: caps,
+		Defaults:     d.Get,
+		Recorder:     events.NewRecorder(manager.GetEventRecorderFor(OperatorName), eventsOpts...),
+		concurrency:  opts.MaxConcurrentReconciles,
+		actions:      make([]controller.Action[wsApi.Workspace], 0),
+		watched:      sets.New[string](),
+		events:       make(chan event.GenericEvent),
+		l:            ctrl.Log.WithName("controller"),
 	}
+	rec.actions = append(rec.actions, NewDeployAction())
+	rec.actions = append(rec.actions, NewProfilesAction())
+	rec.actions = append(rec.actions, NewExposeAction())
 
 	return &rec, nil
 }
@@ -67,82 +111,153 @@ func NewKWorkspaceReconciler(manager ctrl.Manager) (*WorkspaceReconciler, error)
 type WorkspaceReconciler struct {
 	*client.Client
 
-	Scheme      *runtime.Scheme
-	ClusterType controller.ClusterType
-	actions     []controller.Action[wsApi.Workspace]
-	l           logr.Logger
+	Scheme       *runtime.Scheme
+	Capabilities *capabilities.Service
+	Defaults     func() defaults.Workspace
+	Recorder     record.EventRecorder
+	actions      []controller.Action[wsApi.Workspace]
+	concurrency  int
+	l            logr.Logger
+
+	// used to watch the types owned by capabilities aware actions at runtime
+	controller ctrlcontroller.Controller
+	cache      cache.Cache
+	mapper     meta.RESTMapper
+	watchLock  sync.Mutex
+	watched    sets.Set[string]
+	events     chan event.GenericEvent
+
+	// started is set once the controller runs on the elected leader, as the
+	// events channel is not drained before
+	started atomic.Bool
 }
 
 // +kubebuilder:rbac:groups=sco.sco1237896.github.com,resources=workspaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sco.sco1237896.github.com,resources=workspaces/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=sco.sco1237896.github.com,resources=workspaces/finalizers,verbs=update
+// +kubebuilder:rbac:groups=camel.apache.org,resources=integrationplatforms,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=camel.apache.org,resources=integrationprofiles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=camel.apache.org,resources=pipes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=camel.apache.org,resources=kameletbindings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=camel.apache.org,resources=kamelets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=camel.apache.org,resources=integrations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods/log,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="route.openshift.io",resources=routes/custom-host,verbs=create
 // +kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=gateways,verbs=get;list;watch
 
 func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	r.l.Info("Reconciling", "resource", req.NamespacedName.String())
+	ctx, span := tracing.Start(ctx, "Reconcile", trace.WithAttributes(
+		semconv.K8SNamespaceName(req.Namespace),
+		tracing.AttributeWorkspaceName.String(req.Name),
+	))
 
+	res, err := r.reconcile(ctx, req)
+
+	tracing.End(span, err)
+
+	return res, err
+}
+
+func (r *WorkspaceReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	rr := controller.ReconciliationRequest[wsApi.Workspace]{
 		Client: r.Client,
 		NamespacedName: types.NamespacedName{
 			Name:      req.Name,
 			Namespace: req.Namespace,
 		},
-		ClusterType: r.ClusterType,
-		Resource:    &wsApi.Workspace{},
+		Capabilities: r.Capabilities.Get(),
+		Resource:     &wsApi.Workspace{},
+		Recorder:     r.Recorder,
+		Log: r.l.WithValues(
+			controller.LogKeyReconcileID, xid.New().String(),
+			controller.LogKeyResource, req.NamespacedName.String()),
 	}
 
+	rr.CamelAPI = camel.Resolve(rr.Capabilities)
+
+	rr.Log.Info("Reconciling")
+
 	err := r.Get(ctx, req.NamespacedName, rr.Resource)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			// no CR found
 			return ctrl.Result{}, nil
 		}
+
+		return ctrl.Result{}, err
 	}
 
-	reconcileCondition := metav1.Condition{
-		Type:               "Reconcile",
-		Status:             metav1.ConditionTrue,
-		Reason:             "Reconciled",
-		Message:            "Reconciled",
-		ObservedGeneration: rr.Resource.Generation,
+	trace.SpanFromContext(ctx).SetAttributes(tracing.AttributeWorkspaceGeneration.Int64(rr.Resource.Generation))
+
+	rr.Log = rr.Log.WithValues(controller.LogKeyGeneration, rr.Resource.Generation)
+	ctx = logr.NewContext(ctx, rr.Log)
+
+	if !rr.Resource.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &rr)
 	}
+
+	if controllerutil.AddFinalizer(rr.Resource, defaults.FinalizerName) {
+		err := r.Update(ctx, rr.Resource)
+		if err != nil && k8serrors.IsConflict(err) {
+			rr.Log.Info(err.Error())
+			return ctrl.Result{Requeue: true}, nil
+		} else if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// the Workspaces created before the defaulting webhook was installed are
+	// defaulted in memory only, so they behave as if they had been defaulted
+	r.Defaults().Apply(rr.Resource, rr.Capabilities)
+
+	if isPaused(rr.Resource) {
+		return r.pause(ctx, &rr)
+	}
+
+	if conditions.IsTrue(rr.Resource, ConditionTypePaused) {
+		rr.Log.Info("Resuming")
+
+		conditions.MarkFalse(rr.Resource, ConditionTypePaused, "Resumed", "Resumed")
+	}
+
+	rr.Resource.Status.CamelK = camelKStatus(rr.Resource.Status.CamelK, rr.CamelAPI)
+
 	var allErrors error
 
 	for i := range r.actions {
-		if err := r.actions[i].Apply(ctx, &rr); err != nil {
+		if !r.supported(r.actions[i], &rr) {
+			continue
+		}
+
+		if err := r.apply(ctx, r.actions[i], &rr); err != nil {
 			allErrors = multierr.Append(allErrors, err)
 		}
 	}
 
 	if allErrors != nil {
-		reconcileCondition.Status = metav1.ConditionFalse
-		reconcileCondition.Reason = "Failure"
-		reconcileCondition.Message = "Failure"
-
-		rr.Resource.Status.Phase = "Error"
+		conditions.MarkFalse(rr.Resource, ConditionTypeReconcile, "Failure", "Failure")
 	} else {
 		rr.Resource.Status.ObservedGeneration = rr.Resource.Generation
-		rr.Resource.Status.Phase = "Ready"
+
+		conditions.MarkTrue(rr.Resource, ConditionTypeReconcile, "Reconciled", "Reconciled")
 	}
 
-	meta.SetStatusCondition(&rr.Resource.Status.Conditions, reconcileCondition)
+	conditions.Set(rr.Resource, readyCondition(rr.Resource))
 
-	sort.SliceStable(rr.Resource.Status.Conditions, func(i, j int) bool {
-		return rr.Resource.Status.Conditions[i].Type < rr.Resource.Status.Conditions[j].Type
-	})
+	r.setPhase(rr.Resource, nextPhase(rr.Resource))
 
 	// Update status
-	err = r.Status().Update(ctx, rr.Resource)
+	err = r.updateStatus(ctx, rr.Resource)
 	if err != nil && k8serrors.IsConflict(err) {
-		r.l.Info(err.Error())
+		rr.Log.Info(err.Error())
 		return ctrl.Result{Requeue: true}, nil
 	} else if err != nil {
 		allErrors = multierr.Append(allErrors, err)
@@ -151,13 +266,204 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, allErrors
 }
 
+// apply runs the Apply of the given action, recording its duration and failures.
+func (r *WorkspaceReconciler) apply(
+	ctx context.Context,
+	action controller.Action[wsApi.Workspace],
+	rr *controller.ReconciliationRequest[wsApi.Workspace],
+) error {
+	ctx, span := tracing.Start(ctx, "Apply", trace.WithAttributes(tracing.AttributeAction.String(action.Name())))
+	start := time.Now()
+
+	// scope the logger to the action for the duration of the call
+	l := rr.Log
+	rr.Log = l.WithValues(controller.LogKeyAction, action.Name())
+	ctx = logr.NewContext(ctx, rr.Log)
+
+	defer func() {
+		rr.Log = l
+	}()
+
+	err := action.Apply(ctx, rr)
+
+	tracing.End(span, err)
+
+	metrics.ActionDuration.WithLabelValues(action.Name(), metrics.OperationApply).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.ActionFailures.WithLabelValues(action.Name(), metrics.OperationApply, metrics.Reason(err)).Inc()
+	}
+
+	return err
+}
+
+// cleanup runs the Cleanup of the given action, recording its duration and failures.
+func (r *WorkspaceReconciler) cleanup(
+	ctx context.Context,
+	action controller.Action[wsApi.Workspace],
+	rr *controller.ReconciliationRequest[wsApi.Workspace],
+) error {
+	ctx, span := tracing.Start(ctx, "Cleanup", trace.WithAttributes(tracing.AttributeAction.String(action.Name())))
+	start := time.Now()
+
+	// scope the logger to the action for the duration of the call
+	l := rr.Log
+	rr.Log = l.WithValues(controller.LogKeyAction, action.Name())
+	ctx = logr.NewContext(ctx, rr.Log)
+
+	defer func() {
+		rr.Log = l
+	}()
+
+	err := action.Cleanup(ctx, rr)
+
+	tracing.End(span, err)
+
+	metrics.ActionDuration.WithLabelValues(action.Name(), metrics.OperationCleanup).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.ActionFailures.WithLabelValues(action.Name(), metrics.OperationCleanup, metrics.Reason(err)).Inc()
+	}
+
+	return err
+}
+
+// updateStatus updates the status of the Workspace.
+func (r *WorkspaceReconciler) updateStatus(ctx context.Context, ws *wsApi.Workspace) error {
+	ctx, span := tracing.Start(ctx, "UpdateStatus")
+
+	err := r.Status().Update(ctx, ws)
+
+	tracing.End(span, err)
+
+	return err
+}
+
+// pause skips all the actions and only records that the Workspace is paused.
+func (r *WorkspaceReconciler) pause(ctx context.Context, rr *controller.ReconciliationRequest[wsApi.Workspace]) (ctrl.Result, error) {
+	rr.Log.Info("Paused")
+
+	r.setPhase(rr.Resource, wsApi.WorkspacePhasePaused)
+
+	conditions.MarkTrue(rr.Resource, ConditionTypePaused, "Paused", "Reconciliation is paused")
+	conditions.Set(rr.Resource, readyCondition(rr.Resource))
+
+	err := r.updateStatus(ctx, rr.Resource)
+	if err != nil && k8serrors.IsConflict(err) {
+		rr.Log.Info(err.Error())
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	return ctrl.Result{}, err
+}
+
+// setPhase sets the phase of the Workspace and emits an event on transitions.
+func (r *WorkspaceReconciler) setPhase(ws *wsApi.Workspace, phase wsApi.WorkspacePhase) {
+	from := ws.Status.Phase
+
+	if !setPhase(ws, phase) {
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if phase == wsApi.WorkspacePhaseError || phase == wsApi.WorkspacePhaseDegraded {
+		eventType = corev1.EventTypeWarning
+	}
+
+	if from == "" {
+		r.Recorder.Eventf(ws, eventType, EventReasonPhaseChanged, "Phase set to %s", phase)
+	} else {
+		r.Recorder.Eventf(ws, eventType, EventReasonPhaseChanged, "Phase changed from %s to %s", from, phase)
+	}
+}
+
+// isPaused returns true if the Workspace is paused either through its spec or,
+// for emergencies, through the paused annotation.
+func isPaused(ws *wsApi.Workspace) bool {
+	return ws.Spec.Paused || ws.Annotations[defaults.PausedAnnotation] == "true"
+}
+
+// finalize runs the Cleanup of all the actions in reverse order and removes the
+// finalizer only once all of them have succeeded.
+func (r *WorkspaceReconciler) finalize(ctx context.Context, rr *controller.ReconciliationRequest[wsApi.Workspace]) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(rr.Resource, defaults.FinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	policy := rr.Resource.Spec.DeletionPolicy
+	if policy == "" {
+		policy = wsApi.DeletionPolicyDelete
+	}
+
+	rr.Log.Info("Finalizing", "deletionPolicy", policy)
+
+	var allErrors error
+
+	for i := len(r.actions) - 1; i >= 0; i-- {
+		if !r.supported(r.actions[i], rr) {
+			continue
+		}
+
+		if err := r.cleanup(ctx, r.actions[i], rr); err != nil {
+			allErrors = multierr.Append(allErrors, err)
+		}
+	}
+
+	if allErrors != nil {
+		conditions.MarkFalse(rr.Resource, ConditionTypeCleanup, "Failure", "%s", allErrors)
+
+		r.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "CleanupFailed", "Cleanup with deletion policy %s failed: %s", policy, allErrors)
+	} else {
+		for _, o := range rr.Resource.Status.Orphaned {
+			r.Recorder.Eventf(rr.Resource, corev1.EventTypeNormal, "Orphaned", "%s %s/%s orphaned with deletion policy %s", o.Kind, o.Namespace, o.Name, policy)
+		}
+
+		conditions.MarkTrue(rr.Resource, ConditionTypeCleanup, "Cleaned", "Cleaned with deletion policy %s", policy)
+
+		r.Recorder.Eventf(rr.Resource, corev1.EventTypeNormal, "Cleaned", "Cleaned with deletion policy %s", policy)
+	}
+
+	r.setPhase(rr.Resource, wsApi.WorkspacePhaseDeleting)
+
+	conditions.Set(rr.Resource, readyCondition(rr.Resource))
+
+	err := r.updateStatus(ctx, rr.Resource)
+	if err != nil && k8serrors.IsConflict(err) {
+		rr.Log.Info(err.Error())
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		allErrors = multierr.Append(allErrors, err)
+	}
+
+	if allErrors != nil {
+		return ctrl.Result{}, allErrors
+	}
+
+	controllerutil.RemoveFinalizer(rr.Resource, defaults.FinalizerName)
+
+	err = r.Update(ctx, rr.Resource)
+	if err != nil && k8serrors.IsConflict(err) {
+		rr.Log.Info(err.Error())
+		return ctrl.Result{Requeue: true}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *WorkspaceReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	c := ctrl.NewControllerManagedBy(mgr)
+	if err := ctrlmetrics.Registry.Register(&workspaceCollector{reader: mgr.GetClient()}); err != nil {
+		return err
+	}
+
+	c := ctrl.NewControllerManagedBy(mgr).WithOptions(ctrlcontroller.Options{
+		MaxConcurrentReconciles: r.concurrency,
+	})
 
 	c = c.For(&wsApi.Workspace{}, builder.WithPredicates(
 		predicate.Or(
 			predicate.GenerationChangedPredicate{},
+			predicates.AnnotationChanged{Name: defaults.PausedAnnotation},
 		)))
 
 	for i := range r.actions {
@@ -169,5 +475,43 @@ func (r *WorkspaceReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 		c = b
 	}
 
-	return c.Complete(r)
+	// used to find the Workspaces exposed through a Gateway when it changes
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &wsApi.Workspace{}, workspaceGatewayIndex, workspaceGateway); err != nil {
+		return err
+	}
+
+	// Workspaces are enqueued through this channel when the capabilities change
+	c = c.WatchesRawSource(&source.Channel{Source: r.events}, &handler.EnqueueRequestForObject{})
+
+	c = c.WatchesMetadata(crdMetadata(), handler.EnqueueRequestsFromMapFunc(r.crdChanged), builder.WithPredicates(
+		predicate.NewPredicateFuncs(func(obj ctrlclient.Object) bool {
+			return isCapabilityCRD(obj.GetName())
+		})))
+
+	ctl, err := c.Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.controller = ctl
+	r.cache = mgr.GetCache()
+	r.mapper = mgr.GetRESTMapper()
+
+	if err := r.watchOwnedTypes(r.Capabilities.Get()); err != nil {
+		return err
+	}
+
+	// the controller and this runnable are both started once elected leader
+	err = mgr.Add(manager.RunnableFunc(func(_ context.Context) error {
+		r.started.Store(true)
+		return nil
+	}))
+
+	if err != nil {
+		return err
+	}
+
+	r.Capabilities.OnChange(r.capabilitiesChanged)
+
+	return nil
 }
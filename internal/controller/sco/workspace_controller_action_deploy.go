@@ -3,84 +3,152 @@ package sco
 import (
 	"context"
 
-	"github.com/go-logr/logr"
-
-	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/apply"
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
 	"github.com/sco1237896/sco-operator/pkg/controller"
 	"github.com/sco1237896/sco-operator/pkg/controller/client"
-	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
 	camelv1ac "github.com/apache/camel-k/v2/pkg/client/camel/applyconfiguration/camel/v1"
-	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
 )
 
-func NewDeployAction(l logr.Logger) controller.Action[v1alpha1.Workspace] {
-	return &deployAction{
-		logger: l,
-	}
+func NewDeployAction() controller.Action[v1beta1.Workspace] {
+	return &deployAction{}
 }
 
 type deployAction struct {
-	logger logr.Logger
 }
 
-func (a *deployAction) Configure(_ context.Context, _ *client.Client, b *builder.Builder) (*builder.Builder, error) {
-	b = b.Owns(&appsv1.Deployment{}, builder.WithPredicates(
-		predicate.Or(
-			predicate.ResourceVersionChangedPredicate{},
-		)))
+func (a *deployAction) Name() string {
+	return "deploy"
+}
 
+func (a *deployAction) Configure(_ context.Context, _ *client.Client, b *builder.Builder) (*builder.Builder, error) {
 	return b, nil
 }
 
-func (a *deployAction) Cleanup(context.Context, *controller.ReconciliationRequest[v1alpha1.Workspace]) error {
-	return nil
+func (a *deployAction) RequiredCapabilities() []capabilities.Capability {
+	return []capabilities.Capability{capabilities.CamelK}
+}
+
+func (a *deployAction) OwnedTypes(caps capabilities.Capabilities) []ctrlclient.Object {
+	if !caps.Has(capabilities.CamelK) {
+		return nil
+	}
+
+	return []ctrlclient.Object{&camelv1.IntegrationPlatform{}}
 }
 
-func (a *deployAction) Apply(ctx context.Context, rr *controller.ReconciliationRequest[v1alpha1.Workspace]) error {
-	deploymentCondition := metav1.Condition{
-		Type:               "Deployment",
-		Status:             metav1.ConditionTrue,
-		Reason:             "Deployed",
-		Message:            "Deployed",
-		ObservedGeneration: rr.Resource.Generation,
+func (a *deployAction) Cleanup(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	policy := rr.Resource.Spec.DeletionPolicy
+	if policy == "" || policy == v1beta1.DeletionPolicyDelete {
+		return nil
 	}
 
-	err := a.deploy(ctx, rr)
+	platforms := rr.Client.Dynamic.Resource(rr.CamelAPI.IntegrationPlatform.GroupVersionResource).Namespace(rr.Resource.Namespace)
+
+	platform, err := platforms.Get(ctx, rr.Resource.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
 	if err != nil {
-		deploymentCondition.Status = metav1.ConditionFalse
-		deploymentCondition.Reason = "Failure"
-		deploymentCondition.Message = err.Error()
+		return err
+	}
+
+	if orphan(platform, rr.Resource.GetUID(), policy) {
+		if _, err := platforms.Update(ctx, platform, metav1.UpdateOptions{FieldManager: OperatorName}); err != nil {
+			rr.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "OrphanFailed", "Failed to orphan IntegrationPlatform %s: %s", platform.GetName(), err)
+			return err
+		}
+
+		rr.Log.Info("IntegrationPlatform orphaned", "ID", platform.GetUID(), "policy", policy)
 	}
 
-	meta.SetStatusCondition(&rr.Resource.Status.Conditions, deploymentCondition)
+	rr.Resource.Status.Orphaned = appendOrphaned(rr.Resource.Status.Orphaned, v1beta1.ResourceReference{
+		APIVersion: platform.GetAPIVersion(),
+		Kind:       platform.GetKind(),
+		Namespace:  platform.GetNamespace(),
+		Name:       platform.GetName(),
+	})
 
-	return err
+	return nil
 }
 
-func (a *deployAction) deploy(ctx context.Context, rr *controller.ReconciliationRequest[v1alpha1.Workspace]) error {
+func (a *deployAction) Apply(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	platform, err := a.deploy(ctx, rr)
+	if err != nil {
+		rr.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "ApplyFailed", "Failed to apply IntegrationPlatform: %s", err)
+
+		reason := "Failure"
+		if camel.IsUnsupported(err) {
+			// the spec requires a more recent Camel K release
+			reason = "Unsupported"
+		}
+
+		conditions.MarkFalse(rr.Resource, ConditionTypeDeployment, reason, "%s", err)
+		conditions.MarkUnknown(rr.Resource, ConditionTypePlatformReady, "Unknown", "IntegrationPlatform could not be applied")
+
+		return err
+	}
+
+	rr.Recorder.Eventf(rr.Resource, corev1.EventTypeNormal, "IntegrationPlatformApplied", "IntegrationPlatform %s applied", platform.Name)
+
+	conditions.MarkTrue(rr.Resource, ConditionTypeDeployment, "Deployed", "Deployed")
+	conditions.Set(rr.Resource, platformReadyCondition(platform, rr.Resource.Generation))
+
+	if rr.Resource.Status.CamelK != nil && platform.Status.Version != "" {
+		rr.Resource.Status.CamelK.OperatorVersion = platform.Status.Version
+	}
+
+	return nil
+}
+
+// Skipped resets the conditions reporting the IntegrationPlatform, which can't be
+// observed anymore without Camel K.
+func (a *deployAction) Skipped(rr *controller.ReconciliationRequest[v1beta1.Workspace], missing []capabilities.Capability) {
+	conditions.MarkUnknown(rr.Resource, ConditionTypeDeployment, ReasonCapabilityMissing,
+		"IntegrationPlatform not deployed, missing capabilities: %v", missing)
+	conditions.MarkUnknown(rr.Resource, ConditionTypePlatformReady, ReasonCapabilityMissing,
+		"IntegrationPlatform status unknown, missing capabilities: %v", missing)
+}
+
+func (a *deployAction) deploy(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+) (*camelv1.IntegrationPlatform, error) {
 	resource := camelv1ac.IntegrationPlatform(rr.Resource.Name, rr.Resource.Namespace).
-		WithOwnerReferences(metav1ac.OwnerReference().
-			WithAPIVersion(rr.Resource.GetObjectKind().GroupVersionKind().GroupVersion().String()).
-			WithKind(rr.Resource.GetObjectKind().GroupVersionKind().Kind).
-			WithName(rr.Resource.GetName()).
-			WithUID(rr.Resource.GetUID()).
-			WithBlockOwnerDeletion(true).
-			WithController(true)).
+		WithOwnerReferences(apply.WithOwnerReference(rr.Resource)).
 		WithLabels(map[string]string{
 			controller.KubernetesLabelAppName:      rr.Resource.Name,
 			controller.KubernetesLabelAppPartOf:    ApplicationName,
 			controller.KubernetesLabelAppManagedBy: OperatorName,
 		})
 
-	result, err := rr.Client.Camel.CamelV1().IntegrationPlatforms(rr.Resource.Namespace).Apply(
+	if spec := platformSpec(rr.Resource.Spec.Platform); spec != nil {
+		resource = resource.WithSpec(spec)
+	}
+
+	// the IntegrationPlatform is built with the 2.x API and converted to the
+	// API served by the cluster
+	obj, err := rr.CamelAPI.EncodeIntegrationPlatform(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := rr.Client.Dynamic.Resource(rr.CamelAPI.IntegrationPlatform.GroupVersionResource).Namespace(rr.Resource.Namespace).Apply(
 		ctx,
-		resource,
+		obj.GetName(),
+		obj,
 		metav1.ApplyOptions{
 			FieldManager: OperatorName,
 			Force:        true,
@@ -88,10 +156,126 @@ func (a *deployAction) deploy(ctx context.Context, rr *controller.Reconciliation
 	)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	a.logger.Info("IntegrationPlatform applied", "ID", result.UID)
+	result, err := rr.CamelAPI.DecodeIntegrationPlatform(applied)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	rr.Log.Info("IntegrationPlatform applied", "ID", result.UID, "phase", result.Status.Phase)
+
+	return result, nil
+}
+
+// platformReadyCondition mirrors the phase and the conditions of the given IntegrationPlatform
+// into a PlatformReady condition.
+func platformReadyCondition(platform *camelv1.IntegrationPlatform, generation int64) metav1.Condition {
+	c := metav1.Condition{
+		Type:               ConditionTypePlatformReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             string(platform.Status.Phase),
+		Message:            "IntegrationPlatform is " + string(platform.Status.Phase),
+		ObservedGeneration: generation,
+	}
+
+	switch platform.Status.Phase {
+	case camelv1.IntegrationPlatformPhaseReady:
+		c.Status = metav1.ConditionTrue
+	case camelv1.IntegrationPlatformPhaseNone:
+		c.Reason = "Pending"
+		c.Message = "IntegrationPlatform has not been processed yet"
+	default:
+		for i := range platform.Status.Conditions {
+			pc := platform.Status.Conditions[i]
+			if pc.Status != corev1.ConditionTrue && pc.Message != "" {
+				c.Message = string(pc.Type) + ": " + pc.Message
+				break
+			}
+		}
+	}
+
+	return c
+}
+
+func platformSpec(in *v1beta1.PlatformSpec) *camelv1ac.IntegrationPlatformSpecApplyConfiguration {
+	if in == nil || in.Build == nil {
+		return nil
+	}
+
+	build := camelv1ac.IntegrationPlatformBuildSpec()
+
+	if in.Build.Strategy != "" {
+		build = build.WithBuildConfiguration(camelv1ac.BuildConfiguration().
+			WithStrategy(camelv1.BuildStrategy(in.Build.Strategy)))
+	}
+	if in.Build.PublishStrategy != "" {
+		build = build.WithPublishStrategy(camelv1.IntegrationPlatformBuildPublishStrategy(in.Build.PublishStrategy))
+	}
+	if in.Build.Registry != nil {
+		registry := camelv1ac.RegistrySpec().
+			WithAddress(in.Build.Registry.Address).
+			WithInsecure(in.Build.Registry.Insecure)
+
+		if in.Build.Registry.Secret != "" {
+			registry = registry.WithSecret(in.Build.Registry.Secret)
+		}
+
+		build = build.WithRegistry(registry)
+	}
+	if in.Build.BaseImage != "" {
+		build = build.WithBaseImage(in.Build.BaseImage)
+	}
+	if in.Build.Timeout != nil {
+		build = build.WithTimeout(*in.Build.Timeout)
+	}
+	if in.Build.RuntimeVersion != "" {
+		build = build.WithRuntimeVersion(in.Build.RuntimeVersion)
+	}
+
+	return camelv1ac.IntegrationPlatformSpec().WithBuild(build)
+}
+
+// orphan detaches the given object from its owner according to the deletion policy,
+// it returns true if the object has been modified.
+func orphan(obj metav1.Object, owner types.UID, policy v1beta1.DeletionPolicy) bool {
+	changed := false
+
+	refs := make([]metav1.OwnerReference, 0, len(obj.GetOwnerReferences()))
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner {
+			changed = true
+			continue
+		}
+
+		refs = append(refs, ref)
+	}
+
+	obj.SetOwnerReferences(refs)
+
+	if policy == v1beta1.DeletionPolicyRetain {
+		labels := obj.GetLabels()
+
+		for _, l := range []string{controller.KubernetesLabelAppPartOf, controller.KubernetesLabelAppManagedBy} {
+			if _, ok := labels[l]; ok {
+				delete(labels, l)
+				changed = true
+			}
+		}
+
+		obj.SetLabels(labels)
+	}
+
+	return changed
+}
+
+func appendOrphaned(refs []v1beta1.ResourceReference, ref v1beta1.ResourceReference) []v1beta1.ResourceReference {
+	for i := range refs {
+		if refs[i] == ref {
+			return refs
+		}
+	}
+
+	return append(refs, ref)
 }
@@ -0,0 +1,140 @@
+package sco
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+)
+
+func TestPlatformSpec(t *testing.T) {
+	assert.Nil(t, platformSpec(nil))
+	assert.Nil(t, platformSpec(&v1beta1.PlatformSpec{}))
+
+	spec := platformSpec(&v1beta1.PlatformSpec{
+		Build: &v1beta1.BuildSpec{
+			Strategy:        v1beta1.BuildStrategyPod,
+			PublishStrategy: v1beta1.PublishStrategyJib,
+			Registry: &v1beta1.RegistrySpec{
+				Address:  "registry.local:5000",
+				Secret:   "registry-credentials",
+				Insecure: true,
+			},
+			BaseImage:      "eclipse-temurin:17",
+			Timeout:        &metav1.Duration{Duration: 10 * time.Minute},
+			RuntimeVersion: "3.2.0",
+		},
+	})
+
+	assert.NotNil(t, spec)
+	assert.NotNil(t, spec.Build)
+
+	b := spec.Build
+
+	assert.Equal(t, camelv1.BuildStrategyPod, *b.BuildConfiguration.Strategy)
+	assert.Equal(t, camelv1.IntegrationPlatformBuildPublishStrategyJib, *b.PublishStrategy)
+	assert.Equal(t, "registry.local:5000", *b.Registry.Address)
+	assert.Equal(t, "registry-credentials", *b.Registry.Secret)
+	assert.True(t, *b.Registry.Insecure)
+	assert.Equal(t, "eclipse-temurin:17", *b.BaseImage)
+	assert.Equal(t, 10*time.Minute, b.Timeout.Duration)
+	assert.Equal(t, "3.2.0", *b.RuntimeVersion)
+}
+
+func TestPlatformReadyCondition(t *testing.T) {
+	p := camelv1.IntegrationPlatform{}
+
+	c := platformReadyCondition(&p, 1)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, "Pending", c.Reason)
+
+	p.Status.Phase = camelv1.IntegrationPlatformPhaseReady
+
+	c = platformReadyCondition(&p, 2)
+	assert.Equal(t, metav1.ConditionTrue, c.Status)
+	assert.Equal(t, string(camelv1.IntegrationPlatformPhaseReady), c.Reason)
+	assert.Equal(t, int64(2), c.ObservedGeneration)
+
+	p.Status.Phase = camelv1.IntegrationPlatformPhaseError
+	p.Status.Conditions = []camelv1.IntegrationPlatformCondition{{
+		Type:    camelv1.IntegrationPlatformConditionTypeRegistryAvailable,
+		Status:  corev1.ConditionFalse,
+		Message: "registry not found",
+	}}
+
+	c = platformReadyCondition(&p, 3)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, string(camelv1.IntegrationPlatformPhaseError), c.Reason)
+	assert.Equal(t, "RegistryAvailable: registry not found", c.Message)
+}
+
+func TestOrphan(t *testing.T) {
+	newPlatform := func() *camelv1.IntegrationPlatform {
+		return &camelv1.IntegrationPlatform{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					controller.KubernetesLabelAppName:      "ws",
+					controller.KubernetesLabelAppPartOf:    ApplicationName,
+					controller.KubernetesLabelAppManagedBy: OperatorName,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{UID: "ws-uid", Name: "ws"},
+					{UID: "other-uid", Name: "other"},
+				},
+			},
+		}
+	}
+
+	p := newPlatform()
+	assert.True(t, orphan(p, "ws-uid", v1beta1.DeletionPolicyOrphan))
+	assert.Len(t, p.OwnerReferences, 1)
+	assert.Equal(t, types.UID("other-uid"), p.OwnerReferences[0].UID)
+	assert.Len(t, p.Labels, 3)
+	assert.False(t, orphan(p, "ws-uid", v1beta1.DeletionPolicyOrphan))
+
+	p = newPlatform()
+	assert.True(t, orphan(p, "ws-uid", v1beta1.DeletionPolicyRetain))
+	assert.Len(t, p.OwnerReferences, 1)
+	assert.Equal(t, map[string]string{controller.KubernetesLabelAppName: "ws"}, p.Labels)
+}
+
+func TestDeployUnsupported(t *testing.T) {
+	ws := v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "ns"},
+		Spec: v1beta1.WorkspaceSpec{
+			Platform: &v1beta1.PlatformSpec{
+				Build: &v1beta1.BuildSpec{PublishStrategy: v1beta1.PublishStrategyJib},
+			},
+		},
+	}
+
+	rr := controller.ReconciliationRequest[v1beta1.Workspace]{
+		Resource: &ws,
+		Recorder: record.NewFakeRecorder(10),
+		CamelAPI: camel.API{Version: camel.Version1, IntegrationPlatform: camel.IntegrationPlatformsV1},
+		Log:      logr.Discard(),
+	}
+
+	// the IntegrationPlatform is rejected before being applied
+	err := NewDeployAction().Apply(context.Background(), &rr)
+	require.Error(t, err)
+
+	c := conditions.Get(&ws, ConditionTypeDeployment)
+	require.NotNil(t, c)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, "Unsupported", c.Reason)
+	assert.Contains(t, c.Message, `publishStrategy "Jib" is not supported by Camel K 1.x`)
+}
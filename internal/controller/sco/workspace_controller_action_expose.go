@@ -0,0 +1,519 @@
+package sco
+
+import (
+	"context"
+	"net/url"
+	"slices"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routev1ac "github.com/openshift/client-go/route/applyconfigurations/route/v1"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/apply"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/controller/client"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+)
+
+func NewExposeAction() controller.Action[v1beta1.Workspace] {
+	return &exposeAction{}
+}
+
+// exposeAction exposes the HTTP entry point of the Workspace through a Route, an
+// Ingress or an HTTPRoute. As any of the capabilities is enough, it is not
+// capabilities aware and reports the exposure as unsupported instead.
+type exposeAction struct {
+}
+
+// exposure is the outcome of exposing the entry point, Reason and Message explain
+// why the entry point is not reachable yet.
+type exposure struct {
+	Endpoint string
+	Reason   string
+	Message  string
+}
+
+func (a *exposeAction) Name() string {
+	return "expose"
+}
+
+func (a *exposeAction) Configure(_ context.Context, _ *client.Client, b *builder.Builder) (*builder.Builder, error) {
+	return b, nil
+}
+
+func (a *exposeAction) OwnedTypes(caps capabilities.Capabilities) []ctrlclient.Object {
+	answer := make([]ctrlclient.Object, 0)
+
+	for _, mode := range defaults.ExposeModes(caps) {
+		switch mode {
+		case v1beta1.ExposeModeRoute:
+			answer = append(answer, &routev1.Route{})
+		case v1beta1.ExposeModeIngress:
+			answer = append(answer, &networkingv1.Ingress{})
+		case v1beta1.ExposeModeGateway:
+			// HTTPRoutes are not part of the API the operator is built with, so
+			// only their metadata is watched
+			route := metav1.PartialObjectMetadata{}
+			route.SetGroupVersionKind(httpRouteResource(caps).GroupVersion().WithKind(httpRouteKind))
+
+			answer = append(answer, &route)
+		}
+	}
+
+	return answer
+}
+
+func (a *exposeAction) Cleanup(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	policy := rr.Resource.Spec.DeletionPolicy
+	if policy == "" || policy == v1beta1.DeletionPolicyDelete {
+		return nil
+	}
+
+	var allErrors error
+
+	for _, mode := range defaults.ExposeModes(rr.Capabilities) {
+		var err error
+
+		switch mode {
+		case v1beta1.ExposeModeRoute:
+			err = a.orphanRoute(ctx, rr)
+		case v1beta1.ExposeModeIngress:
+			err = a.orphanIngress(ctx, rr)
+		case v1beta1.ExposeModeGateway:
+			err = a.orphanHTTPRoute(ctx, rr)
+		}
+
+		allErrors = multierr.Append(allErrors, err)
+	}
+
+	return allErrors
+}
+
+func (a *exposeAction) orphanRoute(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	routes := rr.Client.Route.RouteV1().Routes(rr.Resource.Namespace)
+
+	route, err := routes.Get(ctx, rr.Resource.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.orphan(rr, route, routev1.GroupVersion.WithKind("Route"), func() error {
+		_, err := routes.Update(ctx, route, metav1.UpdateOptions{FieldManager: OperatorName})
+		return err
+	})
+}
+
+func (a *exposeAction) orphanIngress(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	ingresses := rr.Client.NetworkingV1().Ingresses(rr.Resource.Namespace)
+
+	ingress, err := ingresses.Get(ctx, rr.Resource.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.orphan(rr, ingress, networkingv1.SchemeGroupVersion.WithKind("Ingress"), func() error {
+		_, err := ingresses.Update(ctx, ingress, metav1.UpdateOptions{FieldManager: OperatorName})
+		return err
+	})
+}
+
+func (a *exposeAction) orphanHTTPRoute(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	routes := rr.Client.Dynamic.Resource(httpRouteResource(rr.Capabilities)).Namespace(rr.Resource.Namespace)
+
+	route, err := routes.Get(ctx, rr.Resource.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.orphan(rr, route, route.GroupVersionKind(), func() error {
+		_, err := routes.Update(ctx, route, metav1.UpdateOptions{FieldManager: OperatorName})
+		return err
+	})
+}
+
+// orphan detaches the given object from the Workspace according to the deletion
+// policy, using the given function to persist the changes.
+func (a *exposeAction) orphan(
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	obj metav1.Object,
+	gvk schema.GroupVersionKind,
+	update func() error,
+) error {
+	// a resource with the same name which is not controlled by the Workspace
+	if !metav1.IsControlledBy(obj, rr.Resource) {
+		return nil
+	}
+
+	policy := rr.Resource.Spec.DeletionPolicy
+
+	if orphan(obj, rr.Resource.GetUID(), policy) {
+		if err := update(); err != nil {
+			rr.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "OrphanFailed", "Failed to orphan %s %s: %s", gvk.Kind, obj.GetName(), err)
+			return err
+		}
+
+		rr.Log.Info(gvk.Kind+" orphaned", "ID", obj.GetUID(), "policy", policy)
+	}
+
+	rr.Resource.Status.Orphaned = appendOrphaned(rr.Resource.Status.Orphaned, v1beta1.ResourceReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	})
+
+	return nil
+}
+
+func (a *exposeAction) Apply(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	spec := rr.Resource.Spec.Expose
+
+	if spec == nil {
+		rr.Resource.Status.Endpoint = ""
+		conditions.Delete(rr.Resource, ConditionTypeExposed)
+
+		return a.unexpose(ctx, rr, "")
+	}
+
+	mode, ok := exposeMode(spec, rr.Capabilities)
+	if !ok {
+		rr.Resource.Status.Endpoint = ""
+
+		if spec.Mode == "" {
+			conditions.MarkFalse(rr.Resource, ConditionTypeExposed, "Unsupported",
+				"none of %s, %s or %s are served by the cluster",
+				capabilities.Describe(capabilities.Routes), capabilities.Describe(capabilities.Ingress), capabilities.Describe(capabilities.GatewayAPI))
+		} else {
+			conditions.MarkFalse(rr.Resource, ConditionTypeExposed, "Unsupported",
+				"the %s mode is not supported, the cluster does not serve %s", mode, capabilities.Describe(defaults.ExposeModeCapability(mode)))
+		}
+
+		return nil
+	}
+
+	var e exposure
+	var err error
+
+	switch mode {
+	case v1beta1.ExposeModeRoute:
+		e, err = a.route(ctx, rr, spec)
+	case v1beta1.ExposeModeIngress:
+		e, err = a.ingress(ctx, rr, spec)
+	case v1beta1.ExposeModeGateway:
+		e, err = a.httpRoute(ctx, rr, spec)
+	}
+
+	// remove the resources of the other modes, e.g. after the mode has changed
+	if err == nil {
+		err = a.unexpose(ctx, rr, mode)
+	}
+
+	if err != nil {
+		rr.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "ExposeFailed", "Failed to expose the Workspace: %s", err)
+
+		conditions.MarkFalse(rr.Resource, ConditionTypeExposed, "Failure", "%s", err)
+
+		return err
+	}
+
+	rr.Resource.Status.Endpoint = e.Endpoint
+
+	if e.Reason != "" {
+		conditions.MarkFalse(rr.Resource, ConditionTypeExposed, e.Reason, "%s", e.Message)
+	} else {
+		conditions.MarkTrue(rr.Resource, ConditionTypeExposed, "Exposed", "Exposed at %s", e.Endpoint)
+	}
+
+	return nil
+}
+
+func (a *exposeAction) route(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	spec *v1beta1.ExposeSpec,
+) (exposure, error) {
+	host, err := defaults.RenderHost(spec.Host, rr.Resource)
+	if err != nil {
+		return exposure{}, err
+	}
+
+	routeSpec := routev1ac.RouteSpec().
+		WithTo(routev1ac.RouteTargetReference().WithKind("Service").WithName(spec.Service)).
+		WithPort(routev1ac.RoutePort().WithTargetPort(exposePort(spec)))
+
+	if host != "" {
+		routeSpec = routeSpec.WithHost(host)
+	}
+	// the default path is omitted as passthrough routes do not support paths
+	if spec.Path != "" && spec.Path != "/" {
+		routeSpec = routeSpec.WithPath(spec.Path)
+	}
+	if spec.TLS != nil {
+		termination := spec.TLS.Termination
+		if termination == "" {
+			termination = v1beta1.TLSTerminationEdge
+		}
+
+		policy := routev1.InsecureEdgeTerminationPolicyNone
+		if spec.TLS.InsecureRedirect {
+			policy = routev1.InsecureEdgeTerminationPolicyRedirect
+		}
+
+		routeSpec = routeSpec.WithTLS(routev1ac.TLSConfig().
+			WithTermination(routev1.TLSTerminationType(termination)).
+			WithInsecureEdgeTerminationPolicy(policy))
+	}
+
+	resource := routev1ac.Route(rr.Resource.Name, rr.Resource.Namespace).
+		WithOwnerReferences(apply.WithOwnerReference(rr.Resource)).
+		WithLabels(map[string]string{
+			controller.KubernetesLabelAppName:      rr.Resource.Name,
+			controller.KubernetesLabelAppPartOf:    ApplicationName,
+			controller.KubernetesLabelAppManagedBy: OperatorName,
+		}).
+		WithSpec(routeSpec)
+
+	result, err := rr.Client.Route.RouteV1().Routes(rr.Resource.Namespace).Apply(
+		ctx,
+		resource,
+		metav1.ApplyOptions{
+			FieldManager: OperatorName,
+			Force:        true,
+		},
+	)
+
+	if err != nil {
+		return exposure{}, err
+	}
+
+	rr.Log.Info("Route applied", "ID", result.UID, "host", result.Spec.Host)
+
+	// the host is assigned when the Route is admitted if not set
+	return addressExposure(endpointURL(spec, result.Spec.Host)), nil
+}
+
+func (a *exposeAction) ingress(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	spec *v1beta1.ExposeSpec,
+) (exposure, error) {
+	host, err := defaults.RenderHost(spec.Host, rr.Resource)
+	if err != nil {
+		return exposure{}, err
+	}
+
+	path := spec.Path
+	if path == "" {
+		path = "/"
+	}
+
+	backend := networkingv1ac.IngressServiceBackend().WithName(spec.Service)
+	if port := exposePort(spec); port.Type == intstr.Int {
+		backend = backend.WithPort(networkingv1ac.ServiceBackendPort().WithNumber(port.IntVal))
+	} else {
+		backend = backend.WithPort(networkingv1ac.ServiceBackendPort().WithName(port.StrVal))
+	}
+
+	rule := networkingv1ac.IngressRule().
+		WithHTTP(networkingv1ac.HTTPIngressRuleValue().
+			WithPaths(networkingv1ac.HTTPIngressPath().
+				WithPath(path).
+				WithPathType(networkingv1.PathTypePrefix).
+				WithBackend(networkingv1ac.IngressBackend().WithService(backend))))
+
+	if host != "" {
+		rule = rule.WithHost(host)
+	}
+
+	ingressSpec := networkingv1ac.IngressSpec().WithRules(rule)
+
+	if spec.IngressClassName != "" {
+		ingressSpec = ingressSpec.WithIngressClassName(spec.IngressClassName)
+	}
+	if spec.TLS != nil {
+		tls := networkingv1ac.IngressTLS()
+		if host != "" {
+			tls = tls.WithHosts(host)
+		}
+		if spec.TLS.SecretName != "" {
+			tls = tls.WithSecretName(spec.TLS.SecretName)
+		}
+
+		ingressSpec = ingressSpec.WithTLS(tls)
+	}
+
+	resource := networkingv1ac.Ingress(rr.Resource.Name, rr.Resource.Namespace).
+		WithOwnerReferences(apply.WithOwnerReference(rr.Resource)).
+		WithLabels(map[string]string{
+			controller.KubernetesLabelAppName:      rr.Resource.Name,
+			controller.KubernetesLabelAppPartOf:    ApplicationName,
+			controller.KubernetesLabelAppManagedBy: OperatorName,
+		}).
+		WithSpec(ingressSpec)
+
+	result, err := rr.Client.NetworkingV1().Ingresses(rr.Resource.Namespace).Apply(
+		ctx,
+		resource,
+		metav1.ApplyOptions{
+			FieldManager: OperatorName,
+			Force:        true,
+		},
+	)
+
+	if err != nil {
+		return exposure{}, err
+	}
+
+	rr.Log.Info("Ingress applied", "ID", result.UID, "host", host)
+
+	if host == "" {
+		host = ingressAddress(result)
+	}
+
+	return addressExposure(endpointURL(spec, host)), nil
+}
+
+// unexpose deletes the Route, the Ingress and the HTTPRoute controlled by the
+// Workspace, but the one of the given mode. The owned types are watched, so they
+// are looked up in the cache and only the ones found are deleted.
+func (a *exposeAction) unexpose(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	keep v1beta1.ExposeMode,
+) error {
+	var allErrors error
+
+	owned := a.OwnedTypes(rr.Capabilities)
+
+	for i, mode := range defaults.ExposeModes(rr.Capabilities) {
+		if mode == keep {
+			continue
+		}
+
+		obj := owned[i]
+
+		err := rr.Client.Get(ctx, ctrlclient.ObjectKeyFromObject(rr.Resource), obj)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			allErrors = multierr.Append(allErrors, err)
+			continue
+		}
+
+		// a resource with the same name which is not controlled by the Workspace
+		if !metav1.IsControlledBy(obj, rr.Resource) {
+			continue
+		}
+
+		switch mode {
+		case v1beta1.ExposeModeRoute:
+			err = rr.Client.Route.RouteV1().Routes(rr.Resource.Namespace).Delete(ctx, rr.Resource.Name, metav1.DeleteOptions{})
+		case v1beta1.ExposeModeIngress:
+			err = rr.Client.NetworkingV1().Ingresses(rr.Resource.Namespace).Delete(ctx, rr.Resource.Name, metav1.DeleteOptions{})
+		case v1beta1.ExposeModeGateway:
+			err = rr.Client.Dynamic.Resource(httpRouteResource(rr.Capabilities)).Namespace(rr.Resource.Namespace).Delete(ctx, rr.Resource.Name, metav1.DeleteOptions{})
+		}
+
+		if err != nil && !k8serrors.IsNotFound(err) {
+			allErrors = multierr.Append(allErrors, err)
+		} else {
+			rr.Log.Info("Exposure removed", "mode", mode, "ID", obj.GetUID())
+		}
+	}
+
+	return allErrors
+}
+
+// exposeMode returns the exposure mode to use and whether it is supported by the cluster.
+func exposeMode(spec *v1beta1.ExposeSpec, caps capabilities.Capabilities) (v1beta1.ExposeMode, bool) {
+	if spec.Mode != "" {
+		return spec.Mode, slices.Contains(defaults.ExposeModes(caps), spec.Mode)
+	}
+
+	mode := defaults.ExposeMode(spec, "", caps)
+
+	return mode, mode != ""
+}
+
+// addressExposure returns the exposure of an entry point which is reachable as
+// soon as an address is known.
+func addressExposure(endpoint string) exposure {
+	if endpoint == "" {
+		return exposure{
+			Reason:  "Pending",
+			Message: "Waiting for an address to be assigned",
+		}
+	}
+
+	return exposure{
+		Endpoint: endpoint,
+	}
+}
+
+// exposePort returns the port of the Service to expose, defaulting to http.
+func exposePort(spec *v1beta1.ExposeSpec) intstr.IntOrString {
+	if spec.Port.IntValue() == 0 && spec.Port.StrVal == "" {
+		return intstr.FromString("http")
+	}
+
+	return spec.Port
+}
+
+// ingressAddress returns the address of the load balancer of the given Ingress.
+func ingressAddress(ingress *networkingv1.Ingress) string {
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			return lb.Hostname
+		}
+		if lb.IP != "" {
+			return lb.IP
+		}
+	}
+
+	return ""
+}
+
+// endpointURL returns the URL of the entry point exposed at the given host, or an
+// empty string if the host is not known yet.
+func endpointURL(spec *v1beta1.ExposeSpec, host string) string {
+	if host == "" {
+		return ""
+	}
+
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+		Path:   spec.Path,
+	}
+
+	if spec.TLS != nil {
+		u.Scheme = "https"
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	return u.String()
+}
@@ -0,0 +1,414 @@
+package sco
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/apply"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+)
+
+const (
+	httpRouteKind = "HTTPRoute"
+	gatewayKind   = "Gateway"
+
+	gatewayConditionAccepted   = "Accepted"
+	gatewayConditionProgrammed = "Programmed"
+
+	// workspaceGatewayIndex indexes the Workspaces by the <namespace>/<name> of the
+	// Gateway they are exposed through.
+	workspaceGatewayIndex = "spec.expose.gateway"
+)
+
+// gatewayAPIVersion returns the most recent version of the Gateway API served by the cluster.
+func gatewayAPIVersion(caps capabilities.Capabilities) string {
+	for _, version := range []string{"v1", "v1beta1"} {
+		if caps.HasResource(schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: version, Resource: "httproutes"}) {
+			return version
+		}
+	}
+
+	return ""
+}
+
+func httpRouteResource(caps capabilities.Capabilities) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: gatewayAPIVersion(caps), Resource: "httproutes"}
+}
+
+func gatewayResource(caps capabilities.Capabilities) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: gatewayAPIVersion(caps), Resource: "gateways"}
+}
+
+// gatewayMetadata is used to watch the metadata of the Gateways, which are not
+// part of the API the operator is built with. The resource version changes with
+// the status, hence the Workspaces are notified when a Gateway gets programmed.
+func gatewayMetadata(caps capabilities.Capabilities) *metav1.PartialObjectMetadata {
+	answer := metav1.PartialObjectMetadata{}
+	answer.SetGroupVersionKind(gatewayResource(caps).GroupVersion().WithKind(gatewayKind))
+
+	return &answer
+}
+
+// workspaceGateway returns the index key of the Gateway the given Workspace is
+// exposed through, if any.
+func workspaceGateway(obj ctrlclient.Object) []string {
+	ws, ok := obj.(*v1beta1.Workspace)
+	if !ok || ws.Spec.Expose == nil || ws.Spec.Expose.Gateway == nil {
+		return nil
+	}
+
+	return []string{gatewayNamespace(ws.Spec.Expose.Gateway, ws) + "/" + ws.Spec.Expose.Gateway.Name}
+}
+
+// gatewayChanged enqueues the Workspaces exposed through the given Gateway, so that
+// their exposure is updated when the Gateway is created, programmed or assigned an
+// address.
+func (r *WorkspaceReconciler) gatewayChanged(ctx context.Context, obj ctrlclient.Object) []reconcile.Request {
+	workspaces := v1beta1.WorkspaceList{}
+
+	err := r.List(ctx, &workspaces, ctrlclient.MatchingFields{workspaceGatewayIndex: obj.GetNamespace() + "/" + obj.GetName()})
+	if err != nil {
+		r.l.Error(err, "unable to list the workspaces exposed through a gateway", "gateway", obj.GetNamespace()+"/"+obj.GetName())
+		return nil
+	}
+
+	answer := make([]reconcile.Request, 0, len(workspaces.Items))
+
+	for i := range workspaces.Items {
+		answer = append(answer, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: workspaces.Items[i].Namespace,
+			Name:      workspaces.Items[i].Name,
+		}})
+	}
+
+	return answer
+}
+
+func (a *exposeAction) httpRoute(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	spec *v1beta1.ExposeSpec,
+) (exposure, error) {
+	if spec.Gateway == nil {
+		return exposure{}, fmt.Errorf("the %s mode requires a gateway", v1beta1.ExposeModeGateway)
+	}
+
+	host, err := defaults.RenderHost(spec.Host, rr.Resource)
+	if err != nil {
+		return exposure{}, err
+	}
+
+	// backend references only support port numbers
+	port, err := a.servicePort(ctx, rr, spec)
+	if err != nil {
+		return exposure{}, err
+	}
+
+	obj, err := httpRoute(httpRouteResource(rr.Capabilities).GroupVersion(), rr.Resource, spec, host, port)
+	if err != nil {
+		return exposure{}, err
+	}
+
+	result, err := rr.Client.Dynamic.Resource(httpRouteResource(rr.Capabilities)).Namespace(rr.Resource.Namespace).Apply(
+		ctx,
+		obj.GetName(),
+		obj,
+		metav1.ApplyOptions{
+			FieldManager: OperatorName,
+			Force:        true,
+		},
+	)
+
+	if err != nil {
+		return exposure{}, err
+	}
+
+	rr.Log.Info("HTTPRoute applied", "ID", result.GetUID(), "host", host)
+
+	namespace := gatewayNamespace(spec.Gateway, rr.Resource)
+
+	gateway, err := rr.Client.Dynamic.Resource(gatewayResource(rr.Capabilities)).Namespace(namespace).Get(ctx, spec.Gateway.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return exposure{
+			Reason:  "GatewayNotFound",
+			Message: fmt.Sprintf("Gateway %s/%s not found", namespace, spec.Gateway.Name),
+		}, nil
+	}
+	if err != nil {
+		return exposure{}, err
+	}
+
+	return gatewayExposure(spec, host, result, gateway), nil
+}
+
+// servicePort returns the number of the port of the Service to expose.
+func (a *exposeAction) servicePort(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	spec *v1beta1.ExposeSpec,
+) (int32, error) {
+	port := exposePort(spec)
+	if port.Type == intstr.Int {
+		return port.IntVal, nil
+	}
+
+	svc, err := rr.Client.CoreV1().Services(rr.Resource.Namespace).Get(ctx, spec.Service, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range svc.Spec.Ports {
+		if p.Name == port.StrVal {
+			return p.Port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("service %s has no port named %s", spec.Service, port.StrVal)
+}
+
+func gatewayNamespace(ref *v1beta1.GatewayReference, ws *v1beta1.Workspace) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+
+	return ws.Namespace
+}
+
+// httpRoute returns the HTTPRoute routing the requests for the given host to the Service.
+func httpRoute(
+	gv schema.GroupVersion,
+	owner *v1beta1.Workspace,
+	spec *v1beta1.ExposeSpec,
+	host string,
+	port int32,
+) (*unstructured.Unstructured, error) {
+	ref, err := runtime.DefaultUnstructuredConverter.ToUnstructured(apply.WithOwnerReference(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	parent := map[string]interface{}{
+		"group": capabilities.GatewayAPIGroup,
+		"kind":  gatewayKind,
+		"name":  spec.Gateway.Name,
+	}
+	if spec.Gateway.Namespace != "" {
+		parent["namespace"] = spec.Gateway.Namespace
+	}
+	if spec.Gateway.SectionName != "" {
+		parent["sectionName"] = spec.Gateway.SectionName
+	}
+
+	path := spec.Path
+	if path == "" {
+		path = "/"
+	}
+
+	routeSpec := map[string]interface{}{
+		"parentRefs": []interface{}{parent},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": path,
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": spec.Service,
+						"port": int64(port),
+					},
+				},
+			},
+		},
+	}
+
+	if host != "" {
+		routeSpec["hostnames"] = []interface{}{host}
+	}
+
+	answer := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"ownerReferences": []interface{}{ref},
+			},
+			"spec": routeSpec,
+		},
+	}
+
+	answer.SetGroupVersionKind(gv.WithKind(httpRouteKind))
+	answer.SetName(owner.Name)
+	answer.SetNamespace(owner.Namespace)
+	answer.SetLabels(map[string]string{
+		controller.KubernetesLabelAppName:      owner.Name,
+		controller.KubernetesLabelAppPartOf:    ApplicationName,
+		controller.KubernetesLabelAppManagedBy: OperatorName,
+	})
+
+	return &answer, nil
+}
+
+// gatewayExposure computes the exposure out of the status of the HTTPRoute, which
+// reports whether the Gateway accepted the route, and of the Gateway, which reports
+// whether it is programmed. The endpoint is derived from the listeners of the Gateway.
+func gatewayExposure(spec *v1beta1.ExposeSpec, host string, route *unstructured.Unstructured, gateway *unstructured.Unstructured) exposure {
+	answer := exposure{
+		Endpoint: gatewayEndpoint(spec, host, gateway),
+	}
+
+	name := gateway.GetNamespace() + "/" + gateway.GetName()
+
+	if c := findCondition(gateway.Object, gatewayConditionProgrammed, "status", "conditions"); c == nil || c["status"] != string(metav1.ConditionTrue) {
+		answer.Reason = "GatewayNotProgrammed"
+		answer.Message = conditionMessage(c, "Gateway "+name+" is not programmed")
+
+		return answer
+	}
+
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+
+	var accepted map[string]interface{}
+
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		ref, _, _ := unstructured.NestedString(parent, "parentRef", "name")
+		ns, _, _ := unstructured.NestedString(parent, "parentRef", "namespace")
+		if ns == "" {
+			ns = route.GetNamespace()
+		}
+
+		if ref == gateway.GetName() && ns == gateway.GetNamespace() {
+			accepted = findCondition(parent, gatewayConditionAccepted, "conditions")
+			break
+		}
+	}
+
+	switch {
+	case accepted == nil:
+		answer.Reason = "Pending"
+		answer.Message = "HTTPRoute has not been processed by Gateway " + name + " yet"
+	case accepted["status"] != string(metav1.ConditionTrue):
+		answer.Reason = "NotAccepted"
+		answer.Message = conditionMessage(accepted, "HTTPRoute has not been accepted by Gateway "+name)
+	case answer.Endpoint == "":
+		answer.Reason = "Pending"
+		answer.Message = "Waiting for an address to be assigned to Gateway " + name
+	}
+
+	return answer
+}
+
+// gatewayEndpoint returns the URL of the entry point out of the listeners of the
+// Gateway matching the section name, the host of the listener is used when the
+// host is not set and is not a wildcard, the address of the Gateway otherwise.
+func gatewayEndpoint(spec *v1beta1.ExposeSpec, host string, gateway *unstructured.Unstructured) string {
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+
+	for _, l := range listeners {
+		listener, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(listener, "name")
+		protocol, _, _ := unstructured.NestedString(listener, "protocol")
+
+		if spec.Gateway.SectionName != "" && spec.Gateway.SectionName != name {
+			continue
+		}
+		if protocol != "HTTP" && protocol != "HTTPS" {
+			continue
+		}
+
+		hostname := host
+		if hostname == "" {
+			hostname, _, _ = unstructured.NestedString(listener, "hostname")
+		}
+		if hostname == "" || strings.HasPrefix(hostname, "*") {
+			hostname = gatewayAddress(gateway)
+		}
+		if hostname == "" {
+			return ""
+		}
+
+		scheme := strings.ToLower(protocol)
+
+		port, _, _ := unstructured.NestedInt64(listener, "port")
+		if (scheme == "http" && port != 80) || (scheme == "https" && port != 443) {
+			hostname = net.JoinHostPort(hostname, strconv.FormatInt(port, 10))
+		}
+
+		path := spec.Path
+		if path == "" {
+			path = "/"
+		}
+
+		return scheme + "://" + hostname + path
+	}
+
+	return ""
+}
+
+// gatewayAddress returns the first address of the given Gateway.
+func gatewayAddress(gateway *unstructured.Unstructured) string {
+	addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+
+	for _, a := range addresses {
+		if address, ok := a.(map[string]interface{}); ok {
+			if value, ok := address["value"].(string); ok && value != "" {
+				return value
+			}
+		}
+	}
+
+	return ""
+}
+
+// findCondition returns the condition of the given type within the conditions
+// found at the given path, or nil.
+func findCondition(obj map[string]interface{}, t string, fields ...string) map[string]interface{} {
+	items, _, _ := unstructured.NestedSlice(obj, fields...)
+
+	for _, item := range items {
+		if c, ok := item.(map[string]interface{}); ok && c["type"] == t {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func conditionMessage(c map[string]interface{}, fallback string) string {
+	if c == nil {
+		return fallback
+	}
+
+	if message, ok := c["message"].(string); ok && message != "" {
+		return fallback + ": " + message
+	}
+
+	return fallback
+}
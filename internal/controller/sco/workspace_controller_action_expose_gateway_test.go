@@ -0,0 +1,301 @@
+package sco
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/controller/client"
+)
+
+func TestGatewayAPIVersion(t *testing.T) {
+	assert.Empty(t, gatewayAPIVersion(capabilities.New()))
+
+	caps := capabilities.New(
+		schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1beta1", Resource: "httproutes"},
+	)
+	assert.Equal(t, "v1beta1", gatewayAPIVersion(caps))
+
+	caps = capabilities.New(
+		schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1beta1", Resource: "httproutes"},
+		schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1", Resource: "httproutes"},
+	)
+	assert.Equal(t, "v1", gatewayAPIVersion(caps))
+	assert.Equal(t, "gateway.networking.k8s.io/v1, Resource=gateways", gatewayResource(caps).String())
+}
+
+func TestHTTPRoute(t *testing.T) {
+	owner := v1beta1.Workspace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.GroupVersion.String(),
+			Kind:       "Workspace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws",
+			Namespace: "ns",
+			UID:       "uid",
+		},
+	}
+
+	spec := v1beta1.ExposeSpec{
+		Service: "svc",
+		Gateway: &v1beta1.GatewayReference{
+			Name:        "gw",
+			Namespace:   "infra",
+			SectionName: "https",
+		},
+	}
+
+	r, err := httpRoute(schema.GroupVersion{Group: capabilities.GatewayAPIGroup, Version: "v1"}, &owner, &spec, "ws.example.com", 8080)
+	require.NoError(t, err)
+	assert.Equal(t, "gateway.networking.k8s.io/v1", r.GetAPIVersion())
+	assert.Equal(t, httpRouteKind, r.GetKind())
+	assert.Equal(t, "ws", r.GetName())
+	assert.Equal(t, "ns", r.GetNamespace())
+	assert.Equal(t, "ws", r.GetLabels()[controller.KubernetesLabelAppName])
+	assert.True(t, metav1.IsControlledBy(r, &owner))
+
+	parents, _, _ := unstructured.NestedSlice(r.Object, "spec", "parentRefs")
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"group":       "gateway.networking.k8s.io",
+		"kind":        "Gateway",
+		"name":        "gw",
+		"namespace":   "infra",
+		"sectionName": "https",
+	}}, parents)
+
+	hostnames, _, _ := unstructured.NestedStringSlice(r.Object, "spec", "hostnames")
+	assert.Equal(t, []string{"ws.example.com"}, hostnames)
+
+	rules, _, _ := unstructured.NestedSlice(r.Object, "spec", "rules")
+	require.Len(t, rules, 1)
+	path, _, _ := unstructured.NestedString(rules[0].(map[string]interface{})["matches"].([]interface{})[0].(map[string]interface{}), "path", "value")
+	assert.Equal(t, "/", path)
+	backends := rules[0].(map[string]interface{})["backendRefs"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "svc", "port": int64(8080)}, backends[0])
+
+	r, err = httpRoute(schema.GroupVersion{Group: capabilities.GatewayAPIGroup, Version: "v1"}, &owner, &spec, "", 8080)
+	require.NoError(t, err)
+	_, found, _ := unstructured.NestedFieldNoCopy(r.Object, "spec", "hostnames")
+	assert.False(t, found)
+}
+
+func TestGatewayExposure(t *testing.T) {
+	spec := v1beta1.ExposeSpec{
+		Service: "svc",
+		Gateway: &v1beta1.GatewayReference{Name: "gw"},
+	}
+
+	gateway := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "gw", "namespace": "ns"},
+		"spec": map[string]interface{}{
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "tcp", "protocol": "TCP", "port": int64(9000)},
+				map[string]interface{}{"name": "https", "protocol": "HTTPS", "port": int64(443), "hostname": "*.example.com"},
+			},
+		},
+	}}
+
+	route := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "ws", "namespace": "ns"},
+	}}
+
+	e := gatewayExposure(&spec, "ws.example.com", &route, &gateway)
+	assert.Equal(t, "GatewayNotProgrammed", e.Reason)
+	assert.Equal(t, "https://ws.example.com/", e.Endpoint)
+
+	require.NoError(t, unstructured.SetNestedSlice(gateway.Object, []interface{}{
+		map[string]interface{}{"type": "Programmed", "status": "True"},
+	}, "status", "conditions"))
+
+	e = gatewayExposure(&spec, "ws.example.com", &route, &gateway)
+	assert.Equal(t, "Pending", e.Reason)
+
+	require.NoError(t, unstructured.SetNestedSlice(route.Object, []interface{}{
+		map[string]interface{}{
+			"parentRef": map[string]interface{}{"name": "gw"},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Accepted", "status": "False", "message": "hostname mismatch"},
+			},
+		},
+	}, "status", "parents"))
+
+	e = gatewayExposure(&spec, "ws.example.com", &route, &gateway)
+	assert.Equal(t, "NotAccepted", e.Reason)
+	assert.Equal(t, "HTTPRoute has not been accepted by Gateway ns/gw: hostname mismatch", e.Message)
+
+	require.NoError(t, unstructured.SetNestedSlice(route.Object, []interface{}{
+		map[string]interface{}{
+			"parentRef": map[string]interface{}{"name": "gw"},
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Accepted", "status": "True"},
+			},
+		},
+	}, "status", "parents"))
+
+	e = gatewayExposure(&spec, "ws.example.com", &route, &gateway)
+	assert.Empty(t, e.Reason)
+	assert.Equal(t, "https://ws.example.com/", e.Endpoint)
+
+	// the wildcard hostname of the listener falls back to the address of the gateway
+	e = gatewayExposure(&spec, "", &route, &gateway)
+	assert.Equal(t, "Pending", e.Reason)
+	assert.Empty(t, e.Endpoint)
+
+	require.NoError(t, unstructured.SetNestedSlice(gateway.Object, []interface{}{
+		map[string]interface{}{"type": "IPAddress", "value": "10.0.0.1"},
+	}, "status", "addresses"))
+
+	spec.Gateway.SectionName = "https"
+	spec.Path = "/api"
+
+	e = gatewayExposure(&spec, "", &route, &gateway)
+	assert.Empty(t, e.Reason)
+	assert.Equal(t, "https://10.0.0.1/api", e.Endpoint)
+
+	require.NoError(t, unstructured.SetNestedField(gateway.Object, []interface{}{
+		map[string]interface{}{"name": "http", "protocol": "HTTP", "port": int64(8080), "hostname": "apps.example.com"},
+	}, "spec", "listeners"))
+	spec.Gateway.SectionName = ""
+
+	e = gatewayExposure(&spec, "", &route, &gateway)
+	assert.Equal(t, "http://apps.example.com:8080/api", e.Endpoint)
+}
+
+func TestGatewayProgrammed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	ws := v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "ns"},
+		Spec: v1beta1.WorkspaceSpec{
+			Expose: &v1beta1.ExposeSpec{
+				Mode:    v1beta1.ExposeModeGateway,
+				Service: "svc",
+				Port:    intstr.FromInt(8080),
+				Host:    "ws.example.com",
+				Gateway: &v1beta1.GatewayReference{Name: "gw", Namespace: "infra"},
+			},
+		},
+	}
+
+	other := v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns"},
+		Spec: v1beta1.WorkspaceSpec{
+			Expose: &v1beta1.ExposeSpec{
+				Mode:    v1beta1.ExposeModeGateway,
+				Service: "svc",
+				Gateway: &v1beta1.GatewayReference{Name: "gw"},
+			},
+		},
+	}
+
+	caps := capabilities.New(
+		schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1", Resource: "httproutes"},
+		schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1", Resource: "gateways"},
+	)
+
+	gateway := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "https", "protocol": "HTTPS", "port": int64(443)},
+			},
+		},
+	}}
+	gateway.SetGroupVersionKind(gatewayResource(caps).GroupVersion().WithKind(gatewayKind))
+	gateway.SetNamespace("infra")
+	gateway.SetName("gw")
+
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gatewayResource(caps):   "GatewayList",
+		httpRouteResource(caps): "HTTPRouteList",
+	})
+
+	// the plural of Gateway cannot be guessed by the tracker
+	_, err := dc.Resource(gatewayResource(caps)).Namespace("infra").Create(context.Background(), &gateway, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// the fake client does not support server side apply, the route is accepted
+	// as soon as it is applied
+	dc.PrependReactor("patch", "httproutes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		route := unstructured.Unstructured{}
+		route.SetNamespace("ns")
+		route.SetName("ws")
+
+		err := unstructured.SetNestedSlice(route.Object, []interface{}{
+			map[string]interface{}{
+				"parentRef":  map[string]interface{}{"name": "gw", "namespace": "infra"},
+				"conditions": []interface{}{map[string]interface{}{"type": "Accepted", "status": "True"}},
+			},
+		}, "status", "parents")
+
+		return true, &route, err
+	})
+
+	r := WorkspaceReconciler{
+		Client: &client.Client{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(&ws, &other).
+				WithIndex(&v1beta1.Workspace{}, workspaceGatewayIndex, workspaceGateway).
+				Build(),
+			Dynamic: dc,
+		},
+		l: logr.Discard(),
+	}
+
+	rr := controller.ReconciliationRequest[v1beta1.Workspace]{
+		Client:       r.Client,
+		Capabilities: caps,
+		Resource:     &ws,
+		Recorder:     record.NewFakeRecorder(10),
+		Log:          logr.Discard(),
+	}
+
+	action := NewExposeAction()
+
+	require.NoError(t, action.Apply(context.Background(), &rr))
+
+	c := conditions.Get(&ws, ConditionTypeExposed)
+	require.NotNil(t, c)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, "GatewayNotProgrammed", c.Reason)
+
+	// the Gateway gets programmed after the first reconciliation
+	require.NoError(t, unstructured.SetNestedSlice(gateway.Object, []interface{}{
+		map[string]interface{}{"type": "Programmed", "status": "True"},
+	}, "status", "conditions"))
+
+	_, err = dc.Resource(gatewayResource(caps)).Namespace("infra").Update(context.Background(), &gateway, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// only the Workspace exposed through the Gateway is enqueued
+	assert.Equal(t,
+		[]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "ws"}}},
+		r.gatewayChanged(context.Background(), &gateway))
+
+	require.NoError(t, action.Apply(context.Background(), &rr))
+
+	c = conditions.Get(&ws, ConditionTypeExposed)
+	require.NotNil(t, c)
+	assert.Equal(t, metav1.ConditionTrue, c.Status)
+	assert.Equal(t, "https://ws.example.com/", ws.Status.Endpoint)
+}
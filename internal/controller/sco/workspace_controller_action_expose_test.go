@@ -0,0 +1,156 @@
+package sco
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/controller/client"
+)
+
+func TestEndpointURL(t *testing.T) {
+	assert.Empty(t, endpointURL(&v1beta1.ExposeSpec{}, ""))
+	assert.Equal(t, "http://ws.example.com/", endpointURL(&v1beta1.ExposeSpec{}, "ws.example.com"))
+	assert.Equal(t, "https://ws.example.com/api", endpointURL(&v1beta1.ExposeSpec{
+		Path: "/api",
+		TLS:  &v1beta1.TLSSpec{},
+	}, "ws.example.com"))
+}
+
+func TestExposePort(t *testing.T) {
+	assert.Equal(t, intstr.FromString("http"), exposePort(&v1beta1.ExposeSpec{}))
+	assert.Equal(t, intstr.FromInt32(8080), exposePort(&v1beta1.ExposeSpec{Port: intstr.FromInt32(8080)}))
+	assert.Equal(t, intstr.FromString("web"), exposePort(&v1beta1.ExposeSpec{Port: intstr.FromString("web")}))
+}
+
+func TestIngressAddress(t *testing.T) {
+	ingress := networkingv1.Ingress{}
+	assert.Empty(t, ingressAddress(&ingress))
+
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}
+	assert.Equal(t, "10.0.0.1", ingressAddress(&ingress))
+
+	ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{Hostname: "lb.example.com", IP: "10.0.0.1"}}
+	assert.Equal(t, "lb.example.com", ingressAddress(&ingress))
+}
+
+func TestExposeOwnedTypes(t *testing.T) {
+	a := exposeAction{}
+
+	assert.Empty(t, a.OwnedTypes(capabilities.New()))
+
+	types := a.OwnedTypes(capabilities.New(
+		networkingv1.SchemeGroupVersion.WithResource("ingresses"),
+		routev1.GroupVersion.WithResource("routes"),
+	))
+	require.Len(t, types, 2)
+	assert.IsType(t, &routev1.Route{}, types[0])
+	assert.IsType(t, &networkingv1.Ingress{}, types[1])
+
+	types = a.OwnedTypes(capabilities.New(networkingv1.SchemeGroupVersion.WithResource("ingresses")))
+	require.Len(t, types, 1)
+	assert.IsType(t, &networkingv1.Ingress{}, types[0])
+
+	types = a.OwnedTypes(capabilities.New(schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1beta1", Resource: "httproutes"}))
+	require.Len(t, types, 1)
+	assert.Equal(t, "gateway.networking.k8s.io/v1beta1, Kind=HTTPRoute", types[0].GetObjectKind().GroupVersionKind().String())
+}
+
+func TestExposeMode(t *testing.T) {
+	gateway := capabilities.New(schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1", Resource: "httproutes"})
+
+	mode, ok := exposeMode(&v1beta1.ExposeSpec{}, capabilities.New())
+	assert.False(t, ok)
+	assert.Empty(t, mode)
+
+	mode, ok = exposeMode(&v1beta1.ExposeSpec{}, gateway)
+	assert.True(t, ok)
+	assert.Equal(t, v1beta1.ExposeModeGateway, mode)
+
+	mode, ok = exposeMode(&v1beta1.ExposeSpec{Mode: v1beta1.ExposeModeRoute}, gateway)
+	assert.False(t, ok)
+	assert.Equal(t, v1beta1.ExposeModeRoute, mode)
+
+	both := capabilities.New(
+		networkingv1.SchemeGroupVersion.WithResource("ingresses"),
+		schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1", Resource: "httproutes"},
+	)
+
+	mode, ok = exposeMode(&v1beta1.ExposeSpec{}, both)
+	assert.True(t, ok)
+	assert.Equal(t, v1beta1.ExposeModeIngress, mode)
+
+	mode, ok = exposeMode(&v1beta1.ExposeSpec{Mode: v1beta1.ExposeModeGateway}, both)
+	assert.True(t, ok)
+	assert.Equal(t, v1beta1.ExposeModeGateway, mode)
+}
+
+func TestUnexpose(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, routev1.AddToScheme(scheme))
+	require.NoError(t, networkingv1.AddToScheme(scheme))
+
+	ws := v1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "ns", UID: "uid"}}
+
+	route := routev1.Route{ObjectMeta: metav1.ObjectMeta{
+		Name:            "ws",
+		Namespace:       "ns",
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&ws, v1beta1.GroupVersion.WithKind("Workspace"))},
+	}}
+
+	// an Ingress with the same name, which is not controlled by the Workspace
+	ingress := networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "ns"}}
+
+	kc := kubefake.NewSimpleClientset(&ingress)
+	rc := routefake.NewSimpleClientset(&route)
+
+	rr := controller.ReconciliationRequest[v1beta1.Workspace]{
+		Client: &client.Client{
+			Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(&route, &ingress).Build(),
+			Interface: kc,
+			Route:     rc,
+		},
+		Capabilities: capabilities.New(
+			networkingv1.SchemeGroupVersion.WithResource("ingresses"),
+			routev1.GroupVersion.WithResource("routes"),
+		),
+		Resource: &ws,
+		Log:      logr.Discard(),
+	}
+
+	a := exposeAction{}
+
+	require.NoError(t, a.unexpose(context.Background(), &rr, ""))
+
+	_, err := rc.RouteV1().Routes("ns").Get(context.Background(), "ws", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+	_, err = kc.NetworkingV1().Ingresses("ns").Get(context.Background(), "ws", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// nothing is exposed, the API server is not queried
+	kc.ClearActions()
+	rc.ClearActions()
+
+	rr.Client.Client = fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	require.NoError(t, a.unexpose(context.Background(), &rr, ""))
+	assert.Empty(t, kc.Actions())
+	assert.Empty(t, rc.Actions())
+}
@@ -0,0 +1,442 @@
+package sco
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/apply"
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/controller/client"
+)
+
+const profilePhaseReady = "Ready"
+
+func NewProfilesAction() controller.Action[v1beta1.Workspace] {
+	return &profilesAction{}
+}
+
+// profilesAction materializes the profiles of the Workspace as IntegrationProfiles.
+// As IntegrationProfiles are only served by recent Camel K releases, it does not
+// require a dedicated capability but reports the profiles as unsupported when
+// they are requested and not served.
+type profilesAction struct {
+}
+
+func (a *profilesAction) Name() string {
+	return "profiles"
+}
+
+func (a *profilesAction) Configure(_ context.Context, _ *client.Client, b *builder.Builder) (*builder.Builder, error) {
+	return b, nil
+}
+
+func (a *profilesAction) RequiredCapabilities() []capabilities.Capability {
+	return []capabilities.Capability{capabilities.CamelK}
+}
+
+func (a *profilesAction) OwnedTypes(caps capabilities.Capabilities) []ctrlclient.Object {
+	api := camel.Resolve(caps)
+	if !api.IntegrationProfile.Served() {
+		return nil
+	}
+
+	// IntegrationProfiles are not part of the Camel K API the operator is built
+	// with, so only their metadata is watched
+	profile := metav1.PartialObjectMetadata{}
+	profile.SetGroupVersionKind(api.IntegrationProfile.GroupVersionKind())
+
+	return []ctrlclient.Object{&profile}
+}
+
+func (a *profilesAction) Cleanup(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	policy := rr.Resource.Spec.DeletionPolicy
+	if policy == "" || policy == v1beta1.DeletionPolicyDelete || !rr.CamelAPI.IntegrationProfile.Served() {
+		return nil
+	}
+
+	profiles := rr.Client.Dynamic.Resource(rr.CamelAPI.IntegrationProfile.GroupVersionResource).Namespace(rr.Resource.Namespace)
+
+	owned, err := ownedProfiles(ctx, profiles, rr.Resource)
+	if err != nil {
+		return err
+	}
+
+	var allErrors error
+
+	for i := range owned {
+		profile := &owned[i]
+
+		if orphan(profile, rr.Resource.GetUID(), policy) {
+			if _, err := profiles.Update(ctx, profile, metav1.UpdateOptions{FieldManager: OperatorName}); err != nil {
+				rr.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "OrphanFailed", "Failed to orphan IntegrationProfile %s: %s", profile.GetName(), err)
+
+				allErrors = multierr.Append(allErrors, err)
+
+				continue
+			}
+
+			rr.Log.Info("IntegrationProfile orphaned", "ID", profile.GetUID(), "policy", policy)
+		}
+
+		rr.Resource.Status.Orphaned = appendOrphaned(rr.Resource.Status.Orphaned, v1beta1.ResourceReference{
+			APIVersion: profile.GetAPIVersion(),
+			Kind:       profile.GetKind(),
+			Namespace:  profile.GetNamespace(),
+			Name:       profile.GetName(),
+		})
+	}
+
+	return allErrors
+}
+
+func (a *profilesAction) Apply(ctx context.Context, rr *controller.ReconciliationRequest[v1beta1.Workspace]) error {
+	specs := rr.Resource.Spec.Profiles
+
+	if !rr.CamelAPI.IntegrationProfile.Served() {
+		rr.Resource.Status.Profiles = nil
+
+		if len(specs) == 0 {
+			conditions.Delete(rr.Resource, ConditionTypeProfiles)
+		} else {
+			conditions.MarkFalse(rr.Resource, ConditionTypeProfiles, "Unsupported",
+				"IntegrationProfiles are not served by the installed Camel K %s release, use spec.platform instead",
+				rr.CamelAPI.Version)
+		}
+
+		return nil
+	}
+
+	profiles := rr.Client.Dynamic.Resource(rr.CamelAPI.IntegrationProfile.GroupVersionResource).Namespace(rr.Resource.Namespace)
+
+	var allErrors error
+
+	notReady := make([]string, 0)
+	statuses := make([]v1beta1.ProfileStatus, 0, len(specs))
+
+	for i := range specs {
+		status := v1beta1.ProfileStatus{
+			Name: specs[i].Name,
+		}
+
+		// retain the conditions so that their transition time is preserved
+		for _, s := range rr.Resource.Status.Profiles {
+			if s.Name == specs[i].Name {
+				status.Conditions = s.Conditions
+			}
+		}
+
+		profile, err := a.deploy(ctx, rr, profiles, specs[i])
+		if err != nil {
+			rr.Recorder.Eventf(rr.Resource, corev1.EventTypeWarning, "ApplyFailed", "Failed to apply IntegrationProfile %s: %s", specs[i].Name, err)
+
+			conditions.MarkFalse(profileConditions{rr.Resource, &status}, ConditionTypeDeployment, "Failure", "%s", err)
+			conditions.MarkUnknown(profileConditions{rr.Resource, &status}, ConditionTypeReady, "Unknown", "IntegrationProfile could not be applied")
+
+			allErrors = multierr.Append(allErrors, fmt.Errorf("profile %s: %w", specs[i].Name, err))
+			statuses = append(statuses, status)
+
+			continue
+		}
+
+		rr.Recorder.Eventf(rr.Resource, corev1.EventTypeNormal, "IntegrationProfileApplied", "IntegrationProfile %s applied", profile.GetName())
+
+		ready := profileReadyCondition(profile, rr.Resource.Generation)
+		if ready.Status != metav1.ConditionTrue {
+			notReady = append(notReady, specs[i].Name)
+		}
+
+		conditions.MarkTrue(profileConditions{rr.Resource, &status}, ConditionTypeDeployment, "Deployed", "Deployed")
+		conditions.Set(profileConditions{rr.Resource, &status}, ready)
+
+		statuses = append(statuses, status)
+	}
+
+	rr.Resource.Status.Profiles = statuses
+
+	if err := a.prune(ctx, rr, profiles); err != nil {
+		allErrors = multierr.Append(allErrors, err)
+	}
+
+	switch {
+	case allErrors != nil:
+		conditions.MarkFalse(rr.Resource, ConditionTypeProfiles, "Failure", "%s", allErrors)
+	case len(specs) == 0:
+		conditions.Delete(rr.Resource, ConditionTypeProfiles)
+	case len(notReady) > 0:
+		conditions.MarkFalse(rr.Resource, ConditionTypeProfiles, "NotReady", "IntegrationProfiles not ready: %s", strings.Join(notReady, ", "))
+	default:
+		conditions.MarkTrue(rr.Resource, ConditionTypeProfiles, "Ready", "Ready")
+	}
+
+	return allErrors
+}
+
+// Skipped resets the status of the IntegrationProfiles, which can't be observed
+// anymore without Camel K.
+func (a *profilesAction) Skipped(rr *controller.ReconciliationRequest[v1beta1.Workspace], missing []capabilities.Capability) {
+	rr.Resource.Status.Profiles = nil
+
+	if len(rr.Resource.Spec.Profiles) == 0 {
+		conditions.Delete(rr.Resource, ConditionTypeProfiles)
+		return
+	}
+
+	conditions.MarkUnknown(rr.Resource, ConditionTypeProfiles, ReasonCapabilityMissing,
+		"IntegrationProfiles status unknown, missing capabilities: %v", missing)
+}
+
+func (a *profilesAction) deploy(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	profiles dynamic.ResourceInterface,
+	spec v1beta1.ProfileSpec,
+) (*unstructured.Unstructured, error) {
+	// profiles share the namespace of the Workspace, so do not take over
+	// the ones controlled by something else
+	existing, err := profiles.Get(ctx, spec.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		if ref := metav1.GetControllerOf(existing); ref != nil && ref.UID != rr.Resource.GetUID() {
+			return nil, fmt.Errorf("IntegrationProfile %s is controlled by %s %s", spec.Name, ref.Kind, ref.Name)
+		}
+	}
+
+	obj, err := integrationProfile(rr.CamelAPI.IntegrationProfile, rr.Resource, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := profiles.Apply(
+		ctx,
+		obj.GetName(),
+		obj,
+		metav1.ApplyOptions{
+			FieldManager: OperatorName,
+			Force:        true,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rr.Log.Info("IntegrationProfile applied", "ID", result.GetUID(), "name", result.GetName())
+
+	return result, nil
+}
+
+// prune deletes the IntegrationProfiles controlled by the Workspace which are not
+// part of its spec anymore.
+func (a *profilesAction) prune(
+	ctx context.Context,
+	rr *controller.ReconciliationRequest[v1beta1.Workspace],
+	profiles dynamic.ResourceInterface,
+) error {
+	owned, err := ownedProfiles(ctx, profiles, rr.Resource)
+	if err != nil {
+		return err
+	}
+
+	names := sets.New[string]()
+	for i := range rr.Resource.Spec.Profiles {
+		names.Insert(rr.Resource.Spec.Profiles[i].Name)
+	}
+
+	var allErrors error
+
+	for i := range owned {
+		if names.Has(owned[i].GetName()) {
+			continue
+		}
+
+		err := profiles.Delete(ctx, owned[i].GetName(), metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			allErrors = multierr.Append(allErrors, err)
+			continue
+		}
+
+		rr.Log.Info("IntegrationProfile deleted", "ID", owned[i].GetUID(), "name", owned[i].GetName())
+	}
+
+	return allErrors
+}
+
+// ownedProfiles returns the IntegrationProfiles controlled by the given Workspace.
+func ownedProfiles(ctx context.Context, profiles dynamic.ResourceInterface, owner *v1beta1.Workspace) ([]unstructured.Unstructured, error) {
+	selector := labels.SelectorFromSet(map[string]string{
+		controller.KubernetesLabelAppName:      owner.Name,
+		controller.KubernetesLabelAppManagedBy: OperatorName,
+	})
+
+	list, err := profiles.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	answer := make([]unstructured.Unstructured, 0, len(list.Items))
+
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], owner) {
+			answer = append(answer, list.Items[i])
+		}
+	}
+
+	return answer, nil
+}
+
+// integrationProfile returns the IntegrationProfile corresponding to the given profile.
+func integrationProfile(r camel.Resource, owner *v1beta1.Workspace, in v1beta1.ProfileSpec) (*unstructured.Unstructured, error) {
+	ref, err := runtime.DefaultUnstructuredConverter.ToUnstructured(apply.WithOwnerReference(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{}
+
+	if len(in.Traits) > 0 {
+		traits := make(map[string]interface{}, len(in.Traits))
+
+		for name, raw := range in.Traits {
+			var trait interface{}
+			if err := json.Unmarshal(raw.Raw, &trait); err != nil {
+				return nil, fmt.Errorf("invalid configuration of trait %s: %w", name, err)
+			}
+
+			traits[name] = trait
+		}
+
+		spec["traits"] = traits
+	}
+
+	if b := in.Build; b != nil {
+		build := map[string]interface{}{}
+
+		if b.Registry != nil {
+			registry := map[string]interface{}{
+				"address": b.Registry.Address,
+			}
+			if b.Registry.Secret != "" {
+				registry["secret"] = b.Registry.Secret
+			}
+			if b.Registry.Insecure {
+				registry["insecure"] = true
+			}
+
+			build["registry"] = registry
+		}
+		if b.BaseImage != "" {
+			build["baseImage"] = b.BaseImage
+		}
+		if b.Timeout != nil {
+			build["timeout"] = b.Timeout.Duration.String()
+		}
+		if b.RuntimeVersion != "" {
+			build["runtimeVersion"] = b.RuntimeVersion
+		}
+
+		spec["build"] = build
+	}
+
+	if k := in.Kamelet; k != nil && len(k.Repositories) > 0 {
+		repositories := make([]interface{}, 0, len(k.Repositories))
+		for _, uri := range k.Repositories {
+			repositories = append(repositories, map[string]interface{}{"uri": uri})
+		}
+
+		spec["kamelet"] = map[string]interface{}{
+			"repositories": repositories,
+		}
+	}
+
+	answer := unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"ownerReferences": []interface{}{ref},
+			},
+			"spec": spec,
+		},
+	}
+
+	answer.SetGroupVersionKind(r.GroupVersionKind())
+	answer.SetName(in.Name)
+	answer.SetNamespace(owner.Namespace)
+	answer.SetLabels(map[string]string{
+		controller.KubernetesLabelAppName:      owner.Name,
+		controller.KubernetesLabelAppPartOf:    ApplicationName,
+		controller.KubernetesLabelAppManagedBy: OperatorName,
+	})
+
+	return &answer, nil
+}
+
+// profileReadyCondition mirrors the phase and the conditions of the given IntegrationProfile
+// into a Ready condition.
+func profileReadyCondition(profile *unstructured.Unstructured, generation int64) metav1.Condition {
+	phase, _, _ := unstructured.NestedString(profile.Object, "status", "phase")
+
+	c := metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             phase,
+		Message:            "IntegrationProfile is " + phase,
+		ObservedGeneration: generation,
+	}
+
+	switch phase {
+	case profilePhaseReady:
+		c.Status = metav1.ConditionTrue
+	case "":
+		c.Reason = "Pending"
+		c.Message = "IntegrationProfile has not been processed yet"
+	default:
+		items, _, _ := unstructured.NestedSlice(profile.Object, "status", "conditions")
+		for _, item := range items {
+			pc, ok := item.(map[string]interface{})
+			if !ok || pc["status"] == string(corev1.ConditionTrue) || pc["message"] == nil || pc["message"] == "" {
+				continue
+			}
+
+			c.Message = fmt.Sprintf("%v: %v", pc["type"], pc["message"])
+
+			break
+		}
+	}
+
+	return c
+}
+
+// profileConditions exposes the conditions of a profile to the conditions library,
+// they are observed at the generation of the Workspace.
+type profileConditions struct {
+	*v1beta1.Workspace
+
+	status *v1beta1.ProfileStatus
+}
+
+func (p profileConditions) GetConditions() conditions.Conditions {
+	return p.status.Conditions
+}
+
+func (p profileConditions) SetConditions(c conditions.Conditions) {
+	p.status.Conditions = c
+}
@@ -0,0 +1,129 @@
+package sco
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+)
+
+func TestIntegrationProfile(t *testing.T) {
+	owner := v1beta1.Workspace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1beta1.GroupVersion.String(),
+			Kind:       "Workspace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws",
+			Namespace: "ns",
+			UID:       "uid",
+		},
+	}
+
+	p, err := integrationProfile(camel.IntegrationProfilesV1, &owner, v1beta1.ProfileSpec{
+		Name: "fast",
+		Traits: map[string]runtime.RawExtension{
+			"container": {Raw: []byte(`{"limitCPU":"1","port":8081}`)},
+		},
+		Build: &v1beta1.ProfileBuildSpec{
+			Registry:       &v1beta1.RegistrySpec{Address: "registry.local:5000", Insecure: true},
+			Timeout:        &metav1.Duration{Duration: 5 * time.Minute},
+			RuntimeVersion: "3.2.0",
+		},
+		Kamelet: &v1beta1.KameletSpec{
+			Repositories: []string{"github:apache/camel-kamelets/kamelets"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "camel.apache.org/v1", p.GetAPIVersion())
+	assert.Equal(t, camel.KindIntegrationProfile, p.GetKind())
+	assert.Equal(t, "fast", p.GetName())
+	assert.Equal(t, "ns", p.GetNamespace())
+	assert.Equal(t, "ws", p.GetLabels()[controller.KubernetesLabelAppName])
+	assert.True(t, metav1.IsControlledBy(p, &owner))
+
+	port, _, _ := unstructured.NestedInt64(p.Object, "spec", "traits", "container", "port")
+	assert.Equal(t, int64(8081), port)
+	address, _, _ := unstructured.NestedString(p.Object, "spec", "build", "registry", "address")
+	assert.Equal(t, "registry.local:5000", address)
+	timeout, _, _ := unstructured.NestedString(p.Object, "spec", "build", "timeout")
+	assert.Equal(t, "5m0s", timeout)
+	repositories, _, _ := unstructured.NestedSlice(p.Object, "spec", "kamelet", "repositories")
+	assert.Equal(t, []interface{}{map[string]interface{}{"uri": "github:apache/camel-kamelets/kamelets"}}, repositories)
+
+	_, err = integrationProfile(camel.IntegrationProfilesV1, &owner, v1beta1.ProfileSpec{
+		Name: "invalid",
+		Traits: map[string]runtime.RawExtension{
+			"container": {Raw: []byte(`{`)},
+		},
+	})
+	assert.ErrorContains(t, err, "invalid configuration of trait container")
+}
+
+func TestProfileReadyCondition(t *testing.T) {
+	p := unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	c := profileReadyCondition(&p, 1)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, "Pending", c.Reason)
+
+	require.NoError(t, unstructured.SetNestedField(p.Object, "Ready", "status", "phase"))
+
+	c = profileReadyCondition(&p, 2)
+	assert.Equal(t, metav1.ConditionTrue, c.Status)
+	assert.Equal(t, int64(2), c.ObservedGeneration)
+
+	require.NoError(t, unstructured.SetNestedField(p.Object, "Error", "status", "phase"))
+	require.NoError(t, unstructured.SetNestedSlice(p.Object, []interface{}{
+		map[string]interface{}{"type": "Created", "status": "True", "message": "created"},
+		map[string]interface{}{"type": "RegistryAvailable", "status": "False", "message": "registry not found"},
+	}, "status", "conditions"))
+
+	c = profileReadyCondition(&p, 3)
+	assert.Equal(t, metav1.ConditionFalse, c.Status)
+	assert.Equal(t, "Error", c.Reason)
+	assert.Equal(t, "RegistryAvailable: registry not found", c.Message)
+}
+
+func TestProfilesOwnedTypes(t *testing.T) {
+	a := profilesAction{}
+
+	assert.Empty(t, a.OwnedTypes(capabilities.New(camel.IntegrationPlatformsV1.GroupVersionResource)))
+
+	types := a.OwnedTypes(capabilities.New(
+		camel.IntegrationPlatformsV1.GroupVersionResource,
+		camel.IntegrationProfilesV1.GroupVersionResource,
+		camel.PipesV1.GroupVersionResource,
+	))
+
+	require.Len(t, types, 1)
+	assert.Equal(t, camel.IntegrationProfilesV1.GroupVersionKind(), types[0].GetObjectKind().GroupVersionKind())
+}
+
+func TestProfileConditions(t *testing.T) {
+	ws := v1beta1.Workspace{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+	status := v1beta1.ProfileStatus{Name: "fast"}
+
+	conditions.MarkTrue(profileConditions{&ws, &status}, ConditionTypeReady, "Ready", "Ready")
+	conditions.MarkFalse(profileConditions{&ws, &status}, ConditionTypeDeployment, "Failure", "%s", "boom")
+
+	require.Len(t, status.Conditions, 2)
+	assert.Equal(t, ConditionTypeDeployment, status.Conditions[0].Type)
+	assert.Equal(t, "boom", status.Conditions[0].Message)
+	assert.Equal(t, int64(3), status.Conditions[0].ObservedGeneration)
+	assert.Equal(t, ConditionTypeReady, status.Conditions[1].Type)
+
+	// the conditions of the Workspace are left untouched
+	assert.Empty(t, ws.Status.Conditions)
+}
@@ -0,0 +1,195 @@
+package sco
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+)
+
+// crdMetadata is used to watch the metadata of CustomResourceDefinitions
+// without requiring the apiextensions types.
+func crdMetadata() *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apiextensions.k8s.io/v1",
+			Kind:       "CustomResourceDefinition",
+		},
+	}
+}
+
+// isCapabilityCRD returns true if the CustomResourceDefinition with the given
+// name, which is in the form <plural>.<group>, belongs to a known capability.
+func isCapabilityCRD(name string) bool {
+	_, group, ok := strings.Cut(name, ".")
+	return ok && capabilities.Groups().Has(group)
+}
+
+// supported returns true if the cluster provides all the capabilities required by
+// the given action. The availability of each required capability is recorded as
+// a <Capability>Available condition. Skipped actions implementing SkipAware are
+// notified so that they reset the status they own.
+func (r *WorkspaceReconciler) supported(
+	action controller.Action[wsApi.Workspace],
+	rr *controller.ReconciliationRequest[wsApi.Workspace],
+) bool {
+	ca, ok := action.(controller.CapabilitiesAware)
+	if !ok {
+		return true
+	}
+
+	answer := true
+
+	for _, c := range ca.RequiredCapabilities() {
+		if rr.Capabilities.Has(c) {
+			conditions.MarkTrue(rr.Resource, capabilityConditionType(c), "Available", "%s is available", c)
+			continue
+		}
+
+		answer = false
+
+		conditions.MarkFalse(rr.Resource, capabilityConditionType(c), "Missing",
+			"%s is not available, the cluster does not serve %s: action %s skipped",
+			c, capabilities.Describe(c), action.Name())
+	}
+
+	if !answer {
+		missing := rr.Capabilities.Missing(ca.RequiredCapabilities()...)
+
+		rr.Log.Info("Skipping action, required capabilities are missing",
+			controller.LogKeyAction, action.Name(),
+			"missing", missing)
+
+		if sa, ok := action.(controller.SkipAware[wsApi.Workspace]); ok {
+			sa.Skipped(rr, missing)
+		}
+	}
+
+	return answer
+}
+
+// camelKStatus returns the Camel K status matching the given API, the operator
+// version is retained as it is only known once the IntegrationPlatform is applied.
+func camelKStatus(in *wsApi.CamelKStatus, api camel.API) *wsApi.CamelKStatus {
+	if api.Version == camel.VersionUnknown {
+		return nil
+	}
+
+	answer := wsApi.CamelKStatus{}
+	if in != nil {
+		answer = *in
+	}
+
+	answer.Version = string(api.Version)
+
+	return &answer
+}
+
+// watchOwnedTypes watches the types owned by the actions which are served
+// according to the given capabilities, as well as the Gateways the Workspaces
+// are exposed through. Each type is only watched once.
+func (r *WorkspaceReconciler) watchOwnedTypes(caps capabilities.Capabilities) error {
+	r.watchLock.Lock()
+	defer r.watchLock.Unlock()
+
+	for i := range r.actions {
+		oa, ok := r.actions[i].(controller.OwnerAware)
+		if !ok {
+			continue
+		}
+
+		for _, t := range oa.OwnedTypes(caps) {
+			gvk, err := apiutil.GVKForObject(t, r.Scheme)
+			if err != nil {
+				return err
+			}
+
+			if r.watched.Has(gvk.String()) {
+				continue
+			}
+
+			err = r.controller.Watch(
+				source.Kind(r.cache, t),
+				handler.EnqueueRequestForOwner(r.Scheme, r.mapper, &wsApi.Workspace{}, handler.OnlyControllerOwner()),
+				predicate.ResourceVersionChangedPredicate{})
+
+			if err != nil {
+				return err
+			}
+
+			r.watched.Insert(gvk.String())
+		}
+	}
+
+	// Gateways are referenced by the Workspaces rather than owned
+	if gatewayAPIVersion(caps) != "" {
+		gateway := gatewayMetadata(caps)
+
+		if gvk := gateway.GroupVersionKind(); !r.watched.Has(gvk.String()) {
+			err := r.controller.Watch(
+				source.Kind(r.cache, gateway),
+				handler.EnqueueRequestsFromMapFunc(r.gatewayChanged),
+				predicate.ResourceVersionChangedPredicate{})
+
+			if err != nil {
+				return err
+			}
+
+			r.watched.Insert(gvk.String())
+		}
+	}
+
+	return nil
+}
+
+// capabilitiesChanged starts the watches that became possible and triggers a
+// reconciliation of all the Workspaces so that their conditions reflect the
+// new capabilities. The capabilities are refreshed on all the replicas, but the
+// Workspaces are only enqueued once the controller runs: until then, nothing
+// drains the events and the controller enqueues all the Workspaces on start.
+func (r *WorkspaceReconciler) capabilitiesChanged(caps capabilities.Capabilities) {
+	if err := r.watchOwnedTypes(caps); err != nil {
+		r.l.Error(err, "unable to watch owned types")
+	}
+
+	if !r.started.Load() {
+		return
+	}
+
+	workspaces := wsApi.WorkspaceList{}
+	if err := r.List(context.Background(), &workspaces); err != nil {
+		r.l.Error(err, "unable to list workspaces")
+		return
+	}
+
+	// do not block the refresh of the capabilities
+	go func() {
+		for i := range workspaces.Items {
+			r.events <- event.GenericEvent{Object: &workspaces.Items[i]}
+		}
+	}()
+}
+
+// crdChanged refreshes the capabilities when a CustomResourceDefinition belonging
+// to a known capability changes, so that they are detected without waiting for
+// the periodic refresh. Workspaces are enqueued by capabilitiesChanged.
+func (r *WorkspaceReconciler) crdChanged(ctx context.Context, _ ctrlclient.Object) []reconcile.Request {
+	if _, err := r.Capabilities.Refresh(ctx); err != nil {
+		r.l.Error(err, "unable to refresh capabilities")
+	}
+
+	return nil
+}
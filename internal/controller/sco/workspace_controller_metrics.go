@@ -0,0 +1,96 @@
+package sco
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/metrics"
+)
+
+var (
+	workspacesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "", "workspaces"),
+		"Number of Workspaces per namespace and phase.",
+		[]string{"namespace", "phase"},
+		nil,
+	)
+
+	generationLagDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metrics.Namespace, "workspace", "generation_lag"),
+		"Difference between the current and the observed generation of a Workspace.",
+		[]string{"namespace", "name"},
+		nil,
+	)
+
+	phases = []wsApi.WorkspacePhase{
+		wsApi.WorkspacePhasePending,
+		wsApi.WorkspacePhaseProvisioning,
+		wsApi.WorkspacePhaseReady,
+		wsApi.WorkspacePhaseDegraded,
+		wsApi.WorkspacePhasePaused,
+		wsApi.WorkspacePhaseDeleting,
+		wsApi.WorkspacePhaseError,
+	}
+)
+
+// workspaceCollector computes the Workspace metrics at scrape time out of the
+// manager cache, so that deleted Workspaces never leave stale series behind.
+type workspaceCollector struct {
+	reader ctrlclient.Reader
+}
+
+func (c *workspaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- workspacesDesc
+	ch <- generationLagDesc
+}
+
+func (c *workspaceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	list := wsApi.WorkspaceList{}
+	if err := c.reader.List(ctx, &list); err != nil {
+		controller.Log.Error(err, "unable to list workspaces for metrics")
+		return
+	}
+
+	counts := make(map[string]map[wsApi.WorkspacePhase]int)
+
+	for i := range list.Items {
+		ws := list.Items[i]
+
+		if _, ok := counts[ws.Namespace]; !ok {
+			counts[ws.Namespace] = make(map[wsApi.WorkspacePhase]int)
+		}
+
+		phase := ws.Status.Phase
+		if phase == "" {
+			phase = wsApi.WorkspacePhasePending
+		}
+
+		counts[ws.Namespace][phase]++
+
+		ch <- prometheus.MustNewConstMetric(
+			generationLagDesc,
+			prometheus.GaugeValue,
+			float64(ws.Generation-ws.Status.ObservedGeneration),
+			ws.Namespace,
+			ws.Name)
+	}
+
+	for ns, byPhase := range counts {
+		for _, phase := range phases {
+			ch <- prometheus.MustNewConstMetric(
+				workspacesDesc,
+				prometheus.GaugeValue,
+				float64(byPhase[phase]),
+				ns,
+				string(phase))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package sco
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+)
+
+func TestWorkspaceCollector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, wsApi.AddToScheme(scheme))
+
+	newWorkspace := func(ns string, name string, phase wsApi.WorkspacePhase, generation int64, observed int64) *wsApi.Workspace {
+		return &wsApi.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Generation: generation},
+			Status:     wsApi.WorkspaceStatus{Phase: phase, ObservedGeneration: observed},
+		}
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newWorkspace("ns1", "a", wsApi.WorkspacePhaseReady, 1, 1),
+		newWorkspace("ns1", "b", wsApi.WorkspacePhaseReady, 3, 2),
+		newWorkspace("ns2", "c", wsApi.WorkspacePhaseError, 1, 1),
+	).Build()
+
+	expected := `
+# HELP sco_workspace_generation_lag Difference between the current and the observed generation of a Workspace.
+# TYPE sco_workspace_generation_lag gauge
+sco_workspace_generation_lag{name="a",namespace="ns1"} 0
+sco_workspace_generation_lag{name="b",namespace="ns1"} 1
+sco_workspace_generation_lag{name="c",namespace="ns2"} 0
+`
+
+	err := testutil.CollectAndCompare(&workspaceCollector{reader: c}, strings.NewReader(expected), "sco_workspace_generation_lag")
+	assert.NoError(t, err)
+
+	expected = `
+# HELP sco_workspaces Number of Workspaces per namespace and phase.
+# TYPE sco_workspaces gauge
+sco_workspaces{namespace="ns1",phase="Degraded"} 0
+sco_workspaces{namespace="ns1",phase="Deleting"} 0
+sco_workspaces{namespace="ns1",phase="Error"} 0
+sco_workspaces{namespace="ns1",phase="Paused"} 0
+sco_workspaces{namespace="ns1",phase="Pending"} 0
+sco_workspaces{namespace="ns1",phase="Provisioning"} 0
+sco_workspaces{namespace="ns1",phase="Ready"} 2
+sco_workspaces{namespace="ns2",phase="Degraded"} 0
+sco_workspaces{namespace="ns2",phase="Deleting"} 0
+sco_workspaces{namespace="ns2",phase="Error"} 1
+sco_workspaces{namespace="ns2",phase="Paused"} 0
+sco_workspaces{namespace="ns2",phase="Pending"} 0
+sco_workspaces{namespace="ns2",phase="Provisioning"} 0
+sco_workspaces{namespace="ns2",phase="Ready"} 0
+`
+
+	err = testutil.CollectAndCompare(&workspaceCollector{reader: c}, strings.NewReader(expected), "sco_workspaces")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,73 @@
+package sco
+
+import (
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+)
+
+// nextPhase computes the phase of the Workspace out of the conditions set by the
+// reconciler and by the actions. Rules are evaluated in order:
+//
+//   - Deleting, if the Workspace is being deleted
+//   - Paused, if the reconciliation is paused
+//   - Error, if the reconciliation has failed or the IntegrationPlatform is in error
+//   - Ready, if the IntegrationPlatform is ready
+//   - Degraded, if the Workspace was Ready or Degraded and the IntegrationPlatform is not ready anymore,
+//     including when Camel K has been uninstalled
+//   - Pending, if the IntegrationPlatform has not been processed by Camel K yet or Camel K is missing
+//   - Provisioning, otherwise
+func nextPhase(ws *wsApi.Workspace) wsApi.WorkspacePhase {
+	if !ws.DeletionTimestamp.IsZero() {
+		return wsApi.WorkspacePhaseDeleting
+	}
+	if isPaused(ws) {
+		return wsApi.WorkspacePhasePaused
+	}
+	if conditions.IsFalse(ws, ConditionTypeReconcile) {
+		return wsApi.WorkspacePhaseError
+	}
+
+	c := conditions.Get(ws, ConditionTypePlatformReady)
+
+	switch {
+	case c == nil:
+		return wsApi.WorkspacePhasePending
+	case c.Status == metav1.ConditionTrue:
+		return wsApi.WorkspacePhaseReady
+	case c.Reason == string(camelv1.IntegrationPlatformPhaseError) || c.Reason == string(camelv1.IntegrationPlatformPhaseDuplicate):
+		return wsApi.WorkspacePhaseError
+	case ws.Status.Phase == wsApi.WorkspacePhaseReady || ws.Status.Phase == wsApi.WorkspacePhaseDegraded:
+		return wsApi.WorkspacePhaseDegraded
+	case c.Reason == "Pending" || c.Reason == ReasonCapabilityMissing:
+		return wsApi.WorkspacePhasePending
+	default:
+		return wsApi.WorkspacePhaseProvisioning
+	}
+}
+
+// readyCondition summarizes all the conditions of the Workspace into the Ready condition.
+func readyCondition(ws *wsApi.Workspace) metav1.Condition {
+	return conditions.Summary(ws, ConditionTypeReady,
+		conditions.WithNegativePolarityConditions(ConditionTypePaused),
+		conditions.WithSeverity(conditions.SeverityWarning, ConditionTypePlatformReady, ConditionTypeProfiles, ConditionTypeExposed),
+		conditions.WithSeverity(conditions.SeverityInfo, ConditionTypePaused),
+	)
+}
+
+// setPhase sets the phase of the Workspace, updating the transition time only if
+// the phase has changed. It returns true if the phase has changed.
+func setPhase(ws *wsApi.Workspace, phase wsApi.WorkspacePhase) bool {
+	if ws.Status.Phase == phase && ws.Status.LastTransitionTime != nil {
+		return false
+	}
+
+	now := metav1.Now()
+
+	ws.Status.Phase = phase
+	ws.Status.LastTransitionTime = &now
+
+	return true
+}
@@ -0,0 +1,133 @@
+package sco
+
+import (
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+)
+
+func TestNextPhase(t *testing.T) {
+	now := metav1.Now()
+
+	platformReady := func(status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: ConditionTypePlatformReady, Status: status, Reason: reason}
+	}
+	reconciled := func(status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: ConditionTypeReconcile, Status: status, Reason: "Reconciled"}
+	}
+
+	tests := []struct {
+		name     string
+		ws       wsApi.Workspace
+		expected wsApi.WorkspacePhase
+	}{
+		{
+			name:     "deleting",
+			ws:       wsApi.Workspace{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			expected: wsApi.WorkspacePhaseDeleting,
+		},
+		{
+			name:     "paused",
+			ws:       wsApi.Workspace{Spec: wsApi.WorkspaceSpec{Paused: true}},
+			expected: wsApi.WorkspacePhasePaused,
+		},
+		{
+			name: "failed",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionFalse),
+				platformReady(metav1.ConditionTrue, "Ready"),
+			}}},
+			expected: wsApi.WorkspacePhaseError,
+		},
+		{
+			name:     "no platform",
+			ws:       wsApi.Workspace{},
+			expected: wsApi.WorkspacePhasePending,
+		},
+		{
+			name: "platform pending",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionFalse, "Pending"),
+			}}},
+			expected: wsApi.WorkspacePhasePending,
+		},
+		{
+			name: "platform creating",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionFalse, string(camelv1.IntegrationPlatformPhaseCreating)),
+			}}},
+			expected: wsApi.WorkspacePhaseProvisioning,
+		},
+		{
+			name: "platform ready",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionTrue, string(camelv1.IntegrationPlatformPhaseReady)),
+			}}},
+			expected: wsApi.WorkspacePhaseReady,
+		},
+		{
+			name: "platform not ready anymore",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Phase: wsApi.WorkspacePhaseReady, Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionFalse, string(camelv1.IntegrationPlatformPhaseWarming)),
+			}}},
+			expected: wsApi.WorkspacePhaseDegraded,
+		},
+		{
+			name: "platform ready but Camel K missing",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Phase: wsApi.WorkspacePhaseReady, Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionUnknown, ReasonCapabilityMissing),
+			}}},
+			expected: wsApi.WorkspacePhaseDegraded,
+		},
+		{
+			name: "Camel K missing",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Phase: wsApi.WorkspacePhaseProvisioning, Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionUnknown, ReasonCapabilityMissing),
+			}}},
+			expected: wsApi.WorkspacePhasePending,
+		},
+		{
+			name: "platform error",
+			ws: wsApi.Workspace{Status: wsApi.WorkspaceStatus{Phase: wsApi.WorkspacePhaseReady, Conditions: []metav1.Condition{
+				reconciled(metav1.ConditionTrue),
+				platformReady(metav1.ConditionFalse, string(camelv1.IntegrationPlatformPhaseError)),
+			}}},
+			expected: wsApi.WorkspacePhaseError,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextPhase(&tt.ws))
+		})
+	}
+}
+
+func TestSetPhase(t *testing.T) {
+	ws := wsApi.Workspace{}
+
+	setPhase(&ws, wsApi.WorkspacePhasePending)
+	assert.Equal(t, wsApi.WorkspacePhasePending, ws.Status.Phase)
+	assert.NotNil(t, ws.Status.LastTransitionTime)
+
+	last := ws.Status.LastTransitionTime
+
+	setPhase(&ws, wsApi.WorkspacePhasePending)
+	assert.Same(t, last, ws.Status.LastTransitionTime)
+
+	setPhase(&ws, wsApi.WorkspacePhaseReady)
+	assert.Equal(t, wsApi.WorkspacePhaseReady, ws.Status.Phase)
+	assert.NotSame(t, last, ws.Status.LastTransitionTime)
+}
@@ -0,0 +1,376 @@
+package sco
+
+import (
+	"context"
+	"errors"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	wsApi "github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/conditions"
+	"github.com/sco1237896/sco-operator/pkg/controller"
+	"github.com/sco1237896/sco-operator/pkg/controller/client"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+)
+
+type fakeAction struct {
+	required []capabilities.Capability
+}
+
+func (a *fakeAction) Name() string {
+	return "fake"
+}
+
+func (a *fakeAction) Configure(_ context.Context, _ *client.Client, b *builder.Builder) (*builder.Builder, error) {
+	return b, nil
+}
+
+func (a *fakeAction) Apply(_ context.Context, _ *controller.ReconciliationRequest[wsApi.Workspace]) error {
+	return nil
+}
+
+func (a *fakeAction) Cleanup(_ context.Context, _ *controller.ReconciliationRequest[wsApi.Workspace]) error {
+	return nil
+}
+
+func (a *fakeAction) RequiredCapabilities() []capabilities.Capability {
+	return a.required
+}
+
+func TestSupported(t *testing.T) {
+	r := WorkspaceReconciler{}
+
+	rr := controller.ReconciliationRequest[wsApi.Workspace]{
+		Capabilities: capabilities.New(
+			schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+		),
+		Resource: &wsApi.Workspace{},
+		Log:      logr.Discard(),
+	}
+
+	assert.True(t, r.supported(&fakeAction{required: []capabilities.Capability{capabilities.Ingress}}, &rr))
+	assert.True(t, conditions.IsTrue(rr.Resource, "IngressAvailable"))
+
+	assert.False(t, r.supported(&fakeAction{required: []capabilities.Capability{capabilities.Ingress, capabilities.CamelK}}, &rr))
+	assert.True(t, conditions.IsTrue(rr.Resource, "IngressAvailable"))
+	assert.True(t, conditions.IsFalse(rr.Resource, "CamelKAvailable"))
+
+	c := conditions.Get(rr.Resource, "CamelKAvailable")
+	require.NotNil(t, c)
+	assert.Equal(t, "Missing", c.Reason)
+	assert.Contains(t, c.Message, "action fake skipped")
+}
+
+func TestSupportedSkipped(t *testing.T) {
+	r := WorkspaceReconciler{}
+
+	ws := wsApi.Workspace{
+		Spec: wsApi.WorkspaceSpec{
+			Profiles: []wsApi.ProfileSpec{{Name: "p"}},
+		},
+		Status: wsApi.WorkspaceStatus{
+			Phase:    wsApi.WorkspacePhaseReady,
+			Profiles: []wsApi.ProfileStatus{{Name: "p"}},
+		},
+	}
+
+	conditions.MarkTrue(&ws, ConditionTypeReconcile, "Reconciled", "Reconciled")
+	conditions.MarkTrue(&ws, ConditionTypeDeployment, "Deployed", "Deployed")
+	conditions.MarkTrue(&ws, ConditionTypePlatformReady, "Ready", "Ready")
+	conditions.MarkTrue(&ws, ConditionTypeProfiles, "Ready", "Ready")
+
+	// Camel K has been uninstalled
+	rr := controller.ReconciliationRequest[wsApi.Workspace]{
+		Capabilities: capabilities.New(),
+		Resource:     &ws,
+		Log:          logr.Discard(),
+	}
+
+	assert.False(t, r.supported(NewDeployAction(), &rr))
+	assert.False(t, r.supported(NewProfilesAction(), &rr))
+
+	for _, ct := range []conditions.ConditionType{ConditionTypeDeployment, ConditionTypePlatformReady, ConditionTypeProfiles} {
+		c := conditions.Get(&ws, ct)
+		require.NotNil(t, c, ct)
+		assert.Equal(t, metav1.ConditionUnknown, c.Status, ct)
+		assert.Equal(t, ReasonCapabilityMissing, c.Reason, ct)
+	}
+
+	assert.Nil(t, ws.Status.Profiles)
+
+	// the phase and the Ready condition agree
+	assert.Equal(t, wsApi.WorkspacePhaseDegraded, nextPhase(&ws))
+	assert.Equal(t, metav1.ConditionFalse, readyCondition(&ws).Status)
+}
+
+func TestIsCapabilityCRD(t *testing.T) {
+	assert.True(t, isCapabilityCRD("integrationplatforms.camel.apache.org"))
+	assert.True(t, isCapabilityCRD("routes.route.openshift.io"))
+	assert.False(t, isCapabilityCRD("workspaces.sco.sco1237896.github.com"))
+	assert.False(t, isCapabilityCRD("camel.apache.org"))
+	assert.False(t, isCapabilityCRD("invalid"))
+}
+
+func TestCamelKStatus(t *testing.T) {
+	assert.Nil(t, camelKStatus(&wsApi.CamelKStatus{Version: "2.x"}, camel.API{}))
+
+	s := camelKStatus(nil, camel.API{Version: camel.Version1})
+	assert.Equal(t, &wsApi.CamelKStatus{Version: "1.x"}, s)
+
+	s = camelKStatus(&wsApi.CamelKStatus{Version: "1.x", OperatorVersion: "2.0.1"}, camel.API{Version: camel.Version2})
+	assert.Equal(t, &wsApi.CamelKStatus{Version: "2.x", OperatorVersion: "2.0.1"}, s)
+}
+
+func TestCapabilitiesChanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, wsApi.AddToScheme(scheme))
+
+	r := WorkspaceReconciler{
+		Client: &client.Client{
+			Client: fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(&wsApi.Workspace{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ws"}}).
+				Build(),
+		},
+		Scheme: scheme,
+		l:      logr.Discard(),
+		events: make(chan event.GenericEvent),
+	}
+
+	// the controller does not run on this replica, nothing drains the events
+	goroutines := goruntime.NumGoroutine()
+	r.capabilitiesChanged(capabilities.New())
+	assert.Equal(t, goroutines, goruntime.NumGoroutine())
+
+	r.started.Store(true)
+	r.capabilitiesChanged(capabilities.New())
+
+	select {
+	case e := <-r.events:
+		assert.Equal(t, "ws", e.Object.GetName())
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the Workspace has not been enqueued")
+	}
+}
+
+type cleanupAction struct {
+	fakeAction
+
+	name    string
+	err     error
+	cleaned *[]string
+}
+
+func (a *cleanupAction) Name() string {
+	return a.name
+}
+
+func (a *cleanupAction) Cleanup(_ context.Context, _ *controller.ReconciliationRequest[wsApi.Workspace]) error {
+	*a.cleaned = append(*a.cleaned, a.name)
+	return a.err
+}
+
+func newFinalizerReconciler(t *testing.T, funcs interceptor.Funcs, objs ...ctrlclient.Object) *WorkspaceReconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, wsApi.AddToScheme(scheme))
+
+	return &WorkspaceReconciler{
+		Client: &client.Client{
+			Client: interceptor.NewClient(
+				fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithStatusSubresource(&wsApi.Workspace{}).
+					WithObjects(objs...).
+					Build(),
+				funcs),
+		},
+		Scheme:       scheme,
+		Capabilities: capabilities.NewService(nil),
+		Defaults:     func() defaults.Workspace { return defaults.Workspace{} },
+		Recorder:     record.NewFakeRecorder(10),
+		l:            logr.Discard(),
+	}
+}
+
+func TestReconcileAddsFinalizer(t *testing.T) {
+	ws := wsApi.Workspace{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ws"}}
+	key := ctrlclient.ObjectKeyFromObject(&ws)
+
+	r := newFinalizerReconciler(t, interceptor.Funcs{}, &ws)
+
+	_, err := r.reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	actual := wsApi.Workspace{}
+	require.NoError(t, r.Get(context.Background(), key, &actual))
+	assert.Equal(t, []string{defaults.FinalizerName}, actual.Finalizers)
+}
+
+func TestReconcileSkipsFinalizer(t *testing.T) {
+	now := metav1.Now()
+
+	deleting := wsApi.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              "deleting",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{"other"},
+		},
+	}
+
+	updates := 0
+
+	r := newFinalizerReconciler(t, interceptor.Funcs{
+		Update: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.UpdateOption) error {
+			updates++
+			return c.Update(ctx, obj, opts...)
+		},
+	}, &deleting)
+
+	// already deleted
+	_, err := r.reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "deleted"}})
+	require.NoError(t, err)
+
+	// being deleted
+	_, err = r.reconcile(context.Background(), ctrl.Request{NamespacedName: ctrlclient.ObjectKeyFromObject(&deleting)})
+	require.NoError(t, err)
+
+	assert.Zero(t, updates)
+
+	actual := wsApi.Workspace{}
+	require.NoError(t, r.Get(context.Background(), ctrlclient.ObjectKeyFromObject(&deleting), &actual))
+	assert.Equal(t, []string{"other"}, actual.Finalizers)
+}
+
+func TestFinalize(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		finalizers []string
+	}{
+		{
+			name:       "cleaned",
+			finalizers: []string{"other"},
+		},
+		{
+			name:       "failed",
+			err:        errors.New("cleanup failed"),
+			finalizers: []string{"other", defaults.FinalizerName},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := metav1.Now()
+
+			ws := wsApi.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "ns",
+					Name:              "ws",
+					DeletionTimestamp: &now,
+					Finalizers:        []string{"other", defaults.FinalizerName},
+				},
+			}
+
+			var calls []string
+
+			r := newFinalizerReconciler(t, interceptor.Funcs{
+				Update: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.UpdateOption) error {
+					calls = append(calls, "update")
+					return c.Update(ctx, obj, opts...)
+				},
+				SubResourceUpdate: func(ctx context.Context, c ctrlclient.Client, subResourceName string, obj ctrlclient.Object, opts ...ctrlclient.SubResourceUpdateOption) error {
+					calls = append(calls, subResourceName)
+					return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+				},
+			}, &ws)
+
+			var cleaned []string
+
+			r.actions = []controller.Action[wsApi.Workspace]{
+				&cleanupAction{name: "first", cleaned: &cleaned},
+				&cleanupAction{name: "second", err: tt.err, cleaned: &cleaned},
+				&cleanupAction{name: "third", cleaned: &cleaned},
+			}
+
+			key := ctrlclient.ObjectKeyFromObject(&ws)
+
+			_, err := r.reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// the cleanups run in reverse order, even after a failure
+			assert.Equal(t, []string{"third", "second", "first"}, cleaned)
+
+			actual := wsApi.Workspace{}
+			require.NoError(t, r.Get(context.Background(), key, &actual))
+			assert.Equal(t, tt.finalizers, actual.Finalizers)
+			assert.Equal(t, wsApi.WorkspacePhaseDeleting, actual.Status.Phase)
+
+			c := conditions.Get(&actual, ConditionTypeCleanup)
+			require.NotNil(t, c)
+
+			if tt.err != nil {
+				assert.Equal(t, metav1.ConditionFalse, c.Status)
+				assert.Equal(t, []string{"status"}, calls)
+			} else {
+				assert.Equal(t, metav1.ConditionTrue, c.Status)
+				// the finalizer is removed only once the status is recorded
+				assert.Equal(t, []string{"status", "update"}, calls)
+			}
+		})
+	}
+}
+
+func TestFinalizeStatusConflict(t *testing.T) {
+	now := metav1.Now()
+
+	ws := wsApi.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "ns",
+			Name:              "ws",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{defaults.FinalizerName},
+		},
+	}
+
+	r := newFinalizerReconciler(t, interceptor.Funcs{
+		SubResourceUpdate: func(_ context.Context, _ ctrlclient.Client, _ string, obj ctrlclient.Object, _ ...ctrlclient.SubResourceUpdateOption) error {
+			return k8serrors.NewConflict(wsApi.GroupVersion.WithResource("workspaces").GroupResource(), obj.GetName(), errors.New("conflict"))
+		},
+	}, &ws)
+
+	key := ctrlclient.ObjectKeyFromObject(&ws)
+
+	res, err := r.reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.True(t, res.Requeue)
+
+	actual := wsApi.Workspace{}
+	require.NoError(t, r.Get(context.Background(), key, &actual))
+	assert.Equal(t, []string{defaults.FinalizerName}, actual.Finalizers)
+}
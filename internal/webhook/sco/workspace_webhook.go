@@ -0,0 +1,396 @@
+package sco
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+)
+
+// +kubebuilder:webhook:path=/mutate-sco-sco1237896-github-com-v1beta1-workspace,mutating=true,failurePolicy=fail,sideEffects=None,groups=sco.sco1237896.github.com,resources=workspaces,verbs=create;update,versions=v1beta1,name=mworkspace.sco1237896.github.com,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-sco-sco1237896-github-com-v1beta1-workspace,mutating=false,failurePolicy=fail,sideEffects=None,groups=sco.sco1237896.github.com,resources=workspaces,verbs=create;update,versions=v1beta1,name=vworkspace.sco1237896.github.com,admissionReviewVersions=v1
+
+// WorkspaceDefaulter sets the fields of the Workspace objects that are left empty
+// to the operator level defaults, so that what is stored is explicit.
+type WorkspaceDefaulter struct {
+	// Defaults returns the defaults currently in use.
+	Defaults func() defaults.Workspace
+	// Capabilities returns the capabilities of the cluster, used to select the
+	// exposure mode.
+	Capabilities func() capabilities.Capabilities
+	// TrustedUser is the user whose updates are left untouched, see trusted.
+	TrustedUser string
+}
+
+var _ admission.CustomDefaulter = &WorkspaceDefaulter{}
+
+func (d *WorkspaceDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	ws, ok := obj.(*v1beta1.Workspace)
+	if !ok {
+		return fmt.Errorf("expected a Workspace but got a %T", obj)
+	}
+
+	if trusted(ctx, d.TrustedUser, ws) {
+		return nil
+	}
+
+	d.Defaults().Apply(ws, d.Capabilities())
+
+	return nil
+}
+
+// WorkspaceValidator validates the Workspace objects before they are persisted,
+// so that mistakes are reported to the user instead of surfacing as failed
+// reconciliations.
+type WorkspaceValidator struct {
+	// Reader is used to look up the IntegrationPlatform of the namespace and its
+	// owner, it should not be backed by a cache to catch concurrent creations.
+	Reader ctrlclient.Reader
+	// TrustedUser is the user whose updates are not validated, see trusted.
+	TrustedUser string
+}
+
+var _ admission.CustomValidator = &WorkspaceValidator{}
+
+// SetupWorkspaceWebhookWithManager registers the Workspace webhooks with the webhook
+// server of the given manager. The updates of the given user, if any, which leave
+// the spec unchanged are neither defaulted nor validated.
+func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, d *defaults.Provider, caps *capabilities.Service, trustedUser string) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1beta1.Workspace{}).
+		WithDefaulter(&WorkspaceDefaulter{Defaults: d.Get, Capabilities: caps.Get, TrustedUser: trustedUser}).
+		WithValidator(&WorkspaceValidator{Reader: mgr.GetAPIReader(), TrustedUser: trustedUser}).
+		Complete()
+}
+
+// trusted returns true when the request is an update issued by the given user,
+// which is meant to be the operator itself, that leaves the spec unchanged. The
+// storage version migration rewrites the objects as they are stored, hence it must
+// not be blocked by the legacy objects that are not valid anymore nor change them.
+// The changes of the spec are always defaulted and validated.
+func trusted(ctx context.Context, user string, ws *v1beta1.Workspace) bool {
+	if user == "" {
+		return false
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil || req.UserInfo.Username != user || req.Operation != admissionv1.Update {
+		return false
+	}
+
+	old := v1beta1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, &old); err != nil {
+		return false
+	}
+
+	return equality.Semantic.DeepEqual(old.Spec, ws.Spec)
+}
+
+func (v *WorkspaceValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ws, ok := obj.(*v1beta1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace but got a %T", obj)
+	}
+
+	errs := validateWorkspace(ws)
+
+	owner, err := v.platformOwner(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+	if owner != "" {
+		errs = append(errs, field.Forbidden(
+			field.NewPath("metadata", "namespace"),
+			fmt.Sprintf("the IntegrationPlatform of namespace %s is already owned by Workspace %s", ws.Namespace, owner)))
+	}
+
+	return nil, invalid(ws, errs)
+}
+
+func (v *WorkspaceValidator) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	old, ok := oldObj.(*v1beta1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace but got a %T", oldObj)
+	}
+	ws, ok := newObj.(*v1beta1.Workspace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Workspace but got a %T", newObj)
+	}
+
+	// the objects being deleted are only updated to remove the finalizers
+	// hence there is no point in rejecting them
+	if !ws.DeletionTimestamp.IsZero() || trusted(ctx, v.TrustedUser, ws) {
+		return nil, nil
+	}
+
+	errs := validateWorkspace(ws)
+	errs = append(errs, validateWorkspaceUpdate(old, ws)...)
+
+	return nil, invalid(ws, errs)
+}
+
+func (v *WorkspaceValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// platformOwner returns the name of the Workspace owning the IntegrationPlatform of
+// the namespace of the given one, if any. Camel K only supports a single platform
+// per namespace and each Workspace owns one. The platforms owned by a Workspace that
+// is being deleted are not taken into account as they are about to be released.
+func (v *WorkspaceValidator) platformOwner(ctx context.Context, ws *v1beta1.Workspace) (string, error) {
+	// only the owner references are needed, which are served by both Camel K 1.x and 2.x
+	platforms := metav1.PartialObjectMetadataList{}
+	platforms.SetGroupVersionKind(camelv1.SchemeGroupVersion.WithKind("IntegrationPlatformList"))
+
+	err := v.Reader.List(ctx, &platforms, ctrlclient.InNamespace(ws.Namespace))
+	if meta.IsNoMatchError(err) || k8serrors.IsNotFound(err) {
+		// Camel K is not installed
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for i := range platforms.Items {
+		ref := metav1.GetControllerOf(&platforms.Items[i])
+		if ref == nil || ref.Kind != "Workspace" || ref.Name == ws.Name {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != v1beta1.GroupVersion.Group {
+			continue
+		}
+
+		owner := v1beta1.Workspace{}
+
+		err := v.Reader.Get(ctx, types.NamespacedName{Namespace: ws.Namespace, Name: ref.Name}, &owner)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if owner.UID == ref.UID && owner.DeletionTimestamp.IsZero() {
+			return owner.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+func invalid(ws *v1beta1.Workspace, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return k8serrors.NewInvalid(v1beta1.GroupVersion.WithKind("Workspace").GroupKind(), ws.Name, errs)
+}
+
+// validateWorkspace checks the consistency of the spec of the given Workspace.
+func validateWorkspace(ws *v1beta1.Workspace) field.ErrorList {
+	spec := field.NewPath("spec")
+
+	errs := field.ErrorList{}
+	errs = append(errs, validatePlatform(ws.Spec.Platform, spec.Child("platform"))...)
+	errs = append(errs, validateProfiles(ws.Spec.Profiles, spec.Child("profiles"))...)
+	errs = append(errs, validateExpose(ws, spec.Child("expose"))...)
+
+	return errs
+}
+
+// validateWorkspaceUpdate rejects the changes of the fields that cannot be changed
+// once the resources of the Workspace have been created. The other build settings,
+// such as the strategy, the base image, the runtime version or the registry, are
+// applied to the IntegrationPlatform and only affect the next builds: the running
+// integrations keep the images they were built with, which Camel K rebuilds on
+// their next change. The images already published cannot be moved to another
+// publish strategy though, so it cannot be changed.
+func validateWorkspaceUpdate(old *v1beta1.Workspace, ws *v1beta1.Workspace) field.ErrorList {
+	errs := field.ErrorList{}
+
+	oldStrategy := publishStrategy(old)
+	newStrategy := publishStrategy(ws)
+
+	// the images already published cannot be moved to another strategy
+	if oldStrategy != "" && oldStrategy != newStrategy {
+		errs = append(errs, field.Invalid(
+			field.NewPath("spec", "platform", "build", "publishStrategy"),
+			newStrategy,
+			"field is immutable once set"))
+	}
+
+	return errs
+}
+
+func publishStrategy(ws *v1beta1.Workspace) v1beta1.PublishStrategy {
+	if ws.Spec.Platform == nil || ws.Spec.Platform.Build == nil {
+		return ""
+	}
+
+	return ws.Spec.Platform.Build.PublishStrategy
+}
+
+func validatePlatform(platform *v1beta1.PlatformSpec, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if platform == nil || platform.Build == nil {
+		return errs
+	}
+
+	build := path.Child("build")
+
+	errs = append(errs, validateRegistry(platform.Build.Registry, build.Child("registry"))...)
+
+	if platform.Build.Timeout != nil && platform.Build.Timeout.Duration <= 0 {
+		errs = append(errs, field.Invalid(build.Child("timeout"), platform.Build.Timeout.Duration.String(), "must be greater than zero"))
+	}
+
+	return errs
+}
+
+func validateRegistry(registry *v1beta1.RegistrySpec, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if registry == nil {
+		return errs
+	}
+
+	if registry.Address == "" {
+		errs = append(errs, field.Required(path.Child("address"), "the address of the registry is required"))
+	}
+	if registry.Secret != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(registry.Secret) {
+			errs = append(errs, field.Invalid(path.Child("secret"), registry.Secret, msg))
+		}
+	}
+
+	return errs
+}
+
+func validateProfiles(profiles []v1beta1.ProfileSpec, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	names := make(map[string]struct{}, len(profiles))
+
+	for i, profile := range profiles {
+		p := path.Index(i)
+
+		if _, ok := names[profile.Name]; ok {
+			errs = append(errs, field.Duplicate(p.Child("name"), profile.Name))
+		}
+
+		names[profile.Name] = struct{}{}
+
+		traits := make([]string, 0, len(profile.Traits))
+		for name := range profile.Traits {
+			traits = append(traits, name)
+		}
+
+		sort.Strings(traits)
+
+		for _, name := range traits {
+			var config map[string]interface{}
+
+			if err := json.Unmarshal(profile.Traits[name].Raw, &config); err != nil {
+				errs = append(errs, field.Invalid(p.Child("traits").Key(name), string(profile.Traits[name].Raw), "must be an object"))
+			}
+		}
+
+		if profile.Build == nil {
+			continue
+		}
+
+		errs = append(errs, validateRegistry(profile.Build.Registry, p.Child("build", "registry"))...)
+
+		if profile.Build.Timeout != nil && profile.Build.Timeout.Duration <= 0 {
+			errs = append(errs, field.Invalid(p.Child("build", "timeout"), profile.Build.Timeout.Duration.String(), "must be greater than zero"))
+		}
+	}
+
+	return errs
+}
+
+func validateExpose(ws *v1beta1.Workspace, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	expose := ws.Spec.Expose
+
+	if expose == nil {
+		return errs
+	}
+
+	if expose.Service == "" {
+		errs = append(errs, field.Required(path.Child("service"), "the Service to expose is required"))
+	}
+
+	if expose.Port.Type == intstr.Int && expose.Port.IntVal != 0 {
+		for _, msg := range validation.IsValidPortNum(int(expose.Port.IntVal)) {
+			errs = append(errs, field.Invalid(path.Child("port"), expose.Port.IntVal, msg))
+		}
+	}
+
+	if expose.Path != "" && !strings.HasPrefix(expose.Path, "/") {
+		errs = append(errs, field.Invalid(path.Child("path"), expose.Path, "must start with /"))
+	}
+
+	if expose.Host != "" {
+		host, err := defaults.RenderHost(expose.Host, ws)
+		switch {
+		case err != nil:
+			errs = append(errs, field.Invalid(path.Child("host"), expose.Host, err.Error()))
+		case ws.Name == "":
+			// the name is not known yet when it is generated by the API server
+		default:
+			for _, msg := range validation.IsDNS1123Subdomain(host) {
+				errs = append(errs, field.Invalid(path.Child("host"), host, msg))
+			}
+		}
+	}
+
+	// the mode may be left empty and selected according to the APIs served by the
+	// cluster, in which case the mode specific fields cannot be checked
+	mode := expose.Mode
+
+	if expose.Gateway != nil && mode != "" && mode != v1beta1.ExposeModeGateway {
+		errs = append(errs, field.Forbidden(path.Child("gateway"), fmt.Sprintf("may only be set when mode is %s", v1beta1.ExposeModeGateway)))
+	}
+	if expose.Gateway == nil && mode == v1beta1.ExposeModeGateway {
+		errs = append(errs, field.Required(path.Child("gateway"), fmt.Sprintf("is required when mode is %s", v1beta1.ExposeModeGateway)))
+	}
+	if expose.Gateway != nil && expose.Gateway.Name == "" {
+		errs = append(errs, field.Required(path.Child("gateway", "name"), "the name of the Gateway is required"))
+	}
+
+	if expose.IngressClassName != "" && mode != "" && mode != v1beta1.ExposeModeIngress {
+		errs = append(errs, field.Forbidden(path.Child("ingressClassName"), fmt.Sprintf("may only be set when mode is %s", v1beta1.ExposeModeIngress)))
+	}
+
+	if expose.TLS != nil {
+		termination := expose.TLS.Termination
+
+		if termination != "" && termination != v1beta1.TLSTerminationEdge && mode != "" && mode != v1beta1.ExposeModeRoute {
+			errs = append(errs, field.NotSupported(path.Child("tls", "termination"), termination, []string{string(v1beta1.TLSTerminationEdge)}))
+		}
+	}
+
+	return errs
+}
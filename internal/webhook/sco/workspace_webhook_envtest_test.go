@@ -0,0 +1,160 @@
+package sco
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+)
+
+// TestWorkspaceWebhook runs the webhook against a real API server, it requires the
+// envtest binaries, see the test target of the Makefile.
+func TestWorkspaceWebhook(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set")
+	}
+
+	root := filepath.Join("..", "..", "..")
+
+	// the scheme enables the conversion webhook of the convertible types
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+
+	env := envtest.Environment{
+		Scheme:                scheme,
+		CRDDirectoryPaths:     []string{filepath.Join(root, "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join(root, "config", "webhook", "manifests.yaml")},
+		},
+	}
+
+	cfg, err := env.Start()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, env.Stop())
+	})
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: "0",
+		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    env.WebhookInstallOptions.LocalServingHost,
+			Port:    env.WebhookInstallOptions.LocalServingPort,
+			CertDir: env.WebhookInstallOptions.LocalServingCertDir,
+		}),
+	})
+	require.NoError(t, err)
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	require.NoError(t, err)
+
+	caps := capabilities.NewService(dc)
+	_, err = caps.Refresh(context.Background())
+	require.NoError(t, err)
+
+	d := defaults.Workspace{
+		BuildStrategy:  v1beta1.BuildStrategyPod,
+		RuntimeVersion: "3.2.0",
+	}
+
+	require.NoError(t, SetupWorkspaceWebhookWithManager(mgr, defaults.NewProvider(d), caps, ""))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go func() {
+		assert.NoError(t, mgr.Start(ctx))
+	}()
+
+	// wait for the webhook server to serve
+	require.Eventually(t, func() bool {
+		address := net.JoinHostPort(env.WebhookInstallOptions.LocalServingHost, fmt.Sprint(env.WebhookInstallOptions.LocalServingPort))
+
+		conn, err := tls.Dial("tcp", address, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+		if err != nil {
+			return false
+		}
+
+		return conn.Close() == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	c, err := ctrlclient.New(cfg, ctrlclient.Options{Scheme: scheme})
+	require.NoError(t, err)
+
+	ws := workspace("ws", "default")
+	ws.Spec.Platform = &v1beta1.PlatformSpec{
+		Build: &v1beta1.BuildSpec{PublishStrategy: v1beta1.PublishStrategyJib},
+	}
+
+	ws.Spec.Expose = &v1beta1.ExposeSpec{Service: "svc"}
+
+	require.NoError(t, c.Create(ctx, ws))
+
+	// the defaults are stored, Ingress is the only exposure API served by envtest
+	assert.Equal(t, v1beta1.BuildStrategyPod, ws.Spec.Platform.Build.Strategy)
+	assert.Equal(t, "3.2.0", ws.Spec.Platform.Build.RuntimeVersion)
+	assert.Equal(t, v1beta1.PublishStrategyJib, ws.Spec.Platform.Build.PublishStrategy)
+	assert.Equal(t, v1beta1.ExposeModeIngress, ws.Spec.Expose.Mode)
+
+	// the Workspace is served by both versions
+	spoke := v1alpha1.Workspace{}
+	require.NoError(t, c.Get(ctx, ctrlclient.ObjectKeyFromObject(ws), &spoke))
+	assert.Equal(t, v1alpha1.BuildStrategyPod, spoke.Spec.Platform.Build.Strategy)
+	assert.Equal(t, v1alpha1.ExposeModeIngress, spoke.Spec.Expose.Mode)
+
+	spoke.Spec.Paused = true
+	require.NoError(t, c.Update(ctx, &spoke))
+	require.NoError(t, c.Get(ctx, ctrlclient.ObjectKeyFromObject(ws), ws))
+	assert.True(t, ws.Spec.Paused)
+
+	// a single Workspace may own the IntegrationPlatform of a namespace
+	err = c.Create(ctx, workspace("other", "default"))
+	require.Error(t, err)
+	assert.True(t, k8serrors.IsInvalid(err))
+
+	ws.Spec.Platform.Build.PublishStrategy = v1beta1.PublishStrategySpectrum
+
+	err = c.Update(ctx, ws)
+	require.Error(t, err)
+	assert.True(t, k8serrors.IsInvalid(err))
+	assert.ErrorContains(t, err, "spec.platform.build.publishStrategy")
+
+	invalid := workspace("invalid", "kube-public")
+	invalid.Spec.Expose = &v1beta1.ExposeSpec{
+		Service: "svc",
+		Mode:    v1beta1.ExposeModeGateway,
+		Path:    "api",
+	}
+
+	err = c.Create(ctx, invalid)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "spec.expose.gateway")
+	assert.ErrorContains(t, err, "spec.expose.path")
+}
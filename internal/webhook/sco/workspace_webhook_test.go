@@ -0,0 +1,316 @@
+package sco
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+)
+
+func workspace(name string, namespace string) *v1beta1.Workspace {
+	return &v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
+
+func fields(errs field.ErrorList) []string {
+	answer := make([]string, 0, len(errs))
+	for _, err := range errs {
+		answer = append(answer, err.Field)
+	}
+
+	return answer
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	ws := workspace("ws", "ns")
+	assert.Empty(t, validateWorkspace(ws))
+
+	ws.Spec.Platform = &v1beta1.PlatformSpec{
+		Build: &v1beta1.BuildSpec{
+			Registry: &v1beta1.RegistrySpec{Secret: "Invalid_Secret"},
+			Timeout:  &metav1.Duration{Duration: -time.Minute},
+		},
+	}
+	ws.Spec.Profiles = []v1beta1.ProfileSpec{
+		{Name: "fast"},
+		{
+			Name: "fast",
+			Traits: map[string]runtime.RawExtension{
+				"container": {Raw: []byte(`{"port":8081}`)},
+				"jvm":       {Raw: []byte(`["-Xmx1g"]`)},
+			},
+		},
+	}
+	ws.Spec.Expose = &v1beta1.ExposeSpec{
+		Mode:             v1beta1.ExposeModeGateway,
+		Port:             intstr.FromInt(70000),
+		Host:             "{{ .Unknown }}",
+		Path:             "api",
+		IngressClassName: "nginx",
+		TLS:              &v1beta1.TLSSpec{Termination: v1beta1.TLSTerminationPassthrough},
+	}
+
+	assert.Equal(t, []string{
+		"spec.platform.build.registry.address",
+		"spec.platform.build.registry.secret",
+		"spec.platform.build.timeout",
+		"spec.profiles[1].name",
+		"spec.profiles[1].traits[jvm]",
+		"spec.expose.service",
+		"spec.expose.port",
+		"spec.expose.path",
+		"spec.expose.host",
+		"spec.expose.gateway",
+		"spec.expose.ingressClassName",
+		"spec.expose.tls.termination",
+	}, fields(validateWorkspace(ws)))
+}
+
+func TestValidateExpose(t *testing.T) {
+	ws := workspace("ws", "ns")
+	ws.Spec.Expose = &v1beta1.ExposeSpec{
+		Service: "svc",
+		Port:    intstr.FromString("http"),
+		Host:    "{{ .Name }}-{{ .Namespace }}.apps.example.com",
+		Path:    "/",
+		TLS:     &v1beta1.TLSSpec{Termination: v1beta1.TLSTerminationReencrypt},
+	}
+
+	// the mode is selected by the operator, so the mode specific fields are not checked
+	assert.Empty(t, validateWorkspace(ws))
+
+	ws.Spec.Expose.Mode = v1beta1.ExposeModeRoute
+	assert.Empty(t, validateWorkspace(ws))
+
+	ws.Spec.Expose.Host = "{{ .Name }}_{{ .Namespace }}"
+	assert.Equal(t, []string{"spec.expose.host"}, fields(validateWorkspace(ws)))
+
+	// the host cannot be checked until the name is generated
+	ws.Name = ""
+	ws.GenerateName = "ws-"
+	assert.Empty(t, validateWorkspace(ws))
+
+	ws.Spec.Expose.Host = ""
+	ws.Spec.Expose.Mode = v1beta1.ExposeModeIngress
+	ws.Spec.Expose.Gateway = &v1beta1.GatewayReference{}
+	assert.Equal(t, []string{
+		"spec.expose.gateway",
+		"spec.expose.gateway.name",
+		"spec.expose.tls.termination",
+	}, fields(validateWorkspace(ws)))
+}
+
+func TestValidateWorkspaceUpdate(t *testing.T) {
+	old := workspace("ws", "ns")
+	ws := old.DeepCopy()
+	ws.Spec.Platform = &v1beta1.PlatformSpec{
+		Build: &v1beta1.BuildSpec{PublishStrategy: v1beta1.PublishStrategyJib},
+	}
+
+	assert.Empty(t, validateWorkspaceUpdate(old, ws))
+
+	old = ws.DeepCopy()
+	ws.Spec.Platform.Build.PublishStrategy = v1beta1.PublishStrategySpectrum
+
+	assert.Equal(t, []string{"spec.platform.build.publishStrategy"}, fields(validateWorkspaceUpdate(old, ws)))
+
+	ws.Spec.Platform = nil
+	assert.Equal(t, []string{"spec.platform.build.publishStrategy"}, fields(validateWorkspaceUpdate(old, ws)))
+}
+
+func TestValidateCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	require.NoError(t, camelv1.AddToScheme(scheme))
+
+	existing := workspace("existing", "ns")
+	existing.UID = "existing-uid"
+
+	deleted := workspace("deleted", "ns2")
+	deleted.UID = "deleted-uid"
+	deleted.Finalizers = []string{defaults.FinalizerName}
+	deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	platform := func(owner *v1beta1.Workspace) *camelv1.IntegrationPlatform {
+		controller := true
+
+		p := camelv1.IntegrationPlatform{
+			ObjectMeta: metav1.ObjectMeta{Name: owner.Name, Namespace: owner.Namespace},
+		}
+
+		p.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: v1beta1.GroupVersion.String(),
+			Kind:       "Workspace",
+			Name:       owner.Name,
+			UID:        owner.UID,
+			Controller: &controller,
+		}}
+
+		return &p
+	}
+
+	v := WorkspaceValidator{
+		Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			existing,
+			deleted,
+			platform(existing),
+			platform(deleted),
+			// a Workspace that does not own any platform yet
+			workspace("pending", "ns3"),
+		).Build(),
+	}
+
+	_, err := v.ValidateCreate(context.Background(), workspace("ws", "other"))
+	require.NoError(t, err)
+
+	// the platform of a Workspace being deleted is about to be released
+	_, err = v.ValidateCreate(context.Background(), workspace("ws", "ns2"))
+	require.NoError(t, err)
+
+	_, err = v.ValidateCreate(context.Background(), workspace("ws", "ns3"))
+	require.NoError(t, err)
+
+	_, err = v.ValidateCreate(context.Background(), workspace("ws", "ns"))
+	require.Error(t, err)
+	assert.True(t, k8serrors.IsInvalid(err))
+	assert.ErrorContains(t, err, "already owned by Workspace existing")
+
+	status, ok := err.(k8serrors.APIStatus)
+	require.True(t, ok)
+	require.Len(t, status.Status().Details.Causes, 1)
+	assert.Equal(t, "metadata.namespace", status.Status().Details.Causes[0].Field)
+}
+
+func TestValidateUpdate(t *testing.T) {
+	v := WorkspaceValidator{}
+
+	old := workspace("ws", "ns")
+	old.Spec.Platform = &v1beta1.PlatformSpec{
+		Build: &v1beta1.BuildSpec{PublishStrategy: v1beta1.PublishStrategyJib},
+	}
+
+	ws := old.DeepCopy()
+	ws.Spec.Platform.Build.PublishStrategy = v1beta1.PublishStrategyS2I
+
+	_, err := v.ValidateUpdate(context.Background(), old, ws)
+	assert.True(t, k8serrors.IsInvalid(err))
+
+	// the finalizers of a Workspace being deleted can always be removed
+	now := metav1.Now()
+	ws.DeletionTimestamp = &now
+
+	_, err = v.ValidateUpdate(context.Background(), old, ws)
+	assert.NoError(t, err)
+}
+
+func TestDefault(t *testing.T) {
+	p := defaults.NewProvider(defaults.Workspace{BuildStrategy: v1beta1.BuildStrategyPod})
+
+	d := WorkspaceDefaulter{
+		Defaults: p.Get,
+		Capabilities: func() capabilities.Capabilities {
+			return capabilities.New(networkingv1.SchemeGroupVersion.WithResource("ingresses"))
+		},
+	}
+
+	ws := workspace("ws", "ns")
+	ws.Spec.Expose = &v1beta1.ExposeSpec{Service: "svc"}
+
+	require.NoError(t, d.Default(context.Background(), ws))
+	assert.Equal(t, v1beta1.BuildStrategyPod, ws.Spec.Platform.Build.Strategy)
+	assert.Equal(t, v1beta1.ExposeModeIngress, ws.Spec.Expose.Mode)
+
+	// the defaults in use are replaced at runtime
+	p.Set(defaults.Workspace{RuntimeVersion: "3.2.0"})
+
+	ws = workspace("ws", "ns")
+
+	require.NoError(t, d.Default(context.Background(), ws))
+	assert.Empty(t, ws.Spec.Platform.Build.Strategy)
+	assert.Equal(t, "3.2.0", ws.Spec.Platform.Build.RuntimeVersion)
+
+	assert.Error(t, d.Default(context.Background(), &v1beta1.WorkspaceList{}))
+}
+
+func TestTrustedUser(t *testing.T) {
+	const operator = "system:serviceaccount:sco-system:sco-operator-service-account"
+
+	// a legacy Workspace that is not valid anymore, rewritten as is by the migration
+	old := workspace("ws", "ns")
+	old.Spec.Expose = &v1beta1.ExposeSpec{Service: "svc", Path: "api"}
+
+	raw, err := json.Marshal(old)
+	require.NoError(t, err)
+
+	request := func(user string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: user},
+				OldObject: runtime.RawExtension{Raw: raw},
+			},
+		})
+	}
+
+	ws := old.DeepCopy()
+
+	v := WorkspaceValidator{TrustedUser: operator}
+
+	_, err = v.ValidateUpdate(request("admin"), old, ws)
+	assert.True(t, k8serrors.IsInvalid(err))
+
+	_, err = v.ValidateUpdate(request(operator), old, ws)
+	assert.NoError(t, err)
+
+	// the changes of the spec are validated, whoever the user
+	changed := ws.DeepCopy()
+	changed.Spec.Expose.Service = "other"
+
+	_, err = v.ValidateUpdate(request(operator), old, changed)
+	assert.True(t, k8serrors.IsInvalid(err))
+
+	// no user is trusted unless configured
+	v.TrustedUser = ""
+
+	_, err = v.ValidateUpdate(request(""), old, ws)
+	assert.True(t, k8serrors.IsInvalid(err))
+
+	d := WorkspaceDefaulter{
+		Defaults: defaults.NewProvider(defaults.Workspace{BuildStrategy: v1beta1.BuildStrategyPod}).Get,
+		Capabilities: func() capabilities.Capabilities {
+			return capabilities.New(networkingv1.SchemeGroupVersion.WithResource("ingresses"))
+		},
+		TrustedUser: operator,
+	}
+
+	require.NoError(t, d.Default(request(operator), ws))
+	assert.Equal(t, old, ws)
+
+	require.NoError(t, d.Default(request(operator), changed))
+	assert.Equal(t, v1beta1.ExposeModeIngress, changed.Spec.Expose.Mode)
+
+	require.NoError(t, d.Default(request("admin"), ws))
+	assert.Equal(t, v1beta1.ExposeModeIngress, ws.Spec.Expose.Mode)
+}
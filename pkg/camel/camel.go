@@ -0,0 +1,124 @@
+package camel
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+)
+
+// Version is the major version of Camel K, as inferred from the served APIs.
+type Version string
+
+const (
+	VersionUnknown Version = ""
+	Version1       Version = "1.x"
+	Version2       Version = "2.x"
+)
+
+const (
+	KindIntegrationPlatform = "IntegrationPlatform"
+	KindIntegrationProfile  = "IntegrationProfile"
+	KindPipe                = "Pipe"
+	KindKameletBinding      = "KameletBinding"
+	KindKamelet             = "Kamelet"
+)
+
+var (
+	IntegrationPlatformsV1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1", Resource: "integrationplatforms"},
+		Kind:                 KindIntegrationPlatform,
+	}
+	IntegrationProfilesV1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1", Resource: "integrationprofiles"},
+		Kind:                 KindIntegrationProfile,
+	}
+	IntegrationProfilesV1Alpha1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1alpha1", Resource: "integrationprofiles"},
+		Kind:                 KindIntegrationProfile,
+	}
+	PipesV1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1", Resource: "pipes"},
+		Kind:                 KindPipe,
+	}
+	KameletBindingsV1Alpha1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1alpha1", Resource: "kameletbindings"},
+		Kind:                 KindKameletBinding,
+	}
+	KameletsV1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1", Resource: "kamelets"},
+		Kind:                 KindKamelet,
+	}
+	KameletsV1Alpha1 = Resource{
+		GroupVersionResource: schema.GroupVersionResource{Group: capabilities.CamelKGroup, Version: "v1alpha1", Resource: "kamelets"},
+		Kind:                 KindKamelet,
+	}
+)
+
+// Resource identifies a Camel K resource at a given API version.
+type Resource struct {
+	schema.GroupVersionResource
+	Kind string
+}
+
+// GroupVersionKind returns the GroupVersionKind of the resource.
+func (r Resource) GroupVersionKind() schema.GroupVersionKind {
+	return r.GroupVersion().WithKind(r.Kind)
+}
+
+// Served returns true if the resource is served by the cluster, the zero
+// value denotes a resource which is not served.
+func (r Resource) Served() bool {
+	return r.Resource != ""
+}
+
+// API describes the Camel K API surface served by a cluster, actions should use
+// the resources it exposes rather than assuming a given API version.
+type API struct {
+	Version Version
+
+	IntegrationPlatform Resource
+	// IntegrationProfile is only served by recent 2.x releases.
+	IntegrationProfile Resource
+	// Pipe is served as KameletBinding by 1.x releases.
+	Pipe    Resource
+	Kamelet Resource
+}
+
+// Resolve selects the Camel K resources to use according to the given capabilities,
+// the returned API has an unknown version if Camel K is not installed.
+func Resolve(caps capabilities.Capabilities) API {
+	if !caps.Has(capabilities.CamelK) {
+		return API{}
+	}
+
+	answer := API{
+		IntegrationPlatform: IntegrationPlatformsV1,
+		IntegrationProfile:  first(caps, IntegrationProfilesV1, IntegrationProfilesV1Alpha1),
+	}
+
+	// Pipes and v1 Kamelets have been introduced by 2.x, which still serves
+	// the deprecated v1alpha1 resources, so prefer the most recent ones
+	if caps.HasResource(PipesV1.GroupVersionResource) {
+		answer.Version = Version2
+		answer.Pipe = PipesV1
+		answer.Kamelet = first(caps, KameletsV1, KameletsV1Alpha1)
+	} else {
+		answer.Version = Version1
+		answer.Pipe = first(caps, KameletBindingsV1Alpha1)
+		answer.Kamelet = first(caps, KameletsV1Alpha1)
+	}
+
+	return answer
+}
+
+// first returns the first of the given resources served by the cluster, or
+// the zero Resource if none is.
+func first(caps capabilities.Capabilities, candidates ...Resource) Resource {
+	for _, c := range candidates {
+		if caps.HasResource(c.GroupVersionResource) {
+			return c
+		}
+	}
+
+	return Resource{}
+}
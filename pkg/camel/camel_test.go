@@ -0,0 +1,142 @@
+package camel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	camelv1ac "github.com/apache/camel-k/v2/pkg/client/camel/applyconfiguration/camel/v1"
+
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+)
+
+func TestResolve(t *testing.T) {
+	api := Resolve(capabilities.New())
+	assert.Equal(t, VersionUnknown, api.Version)
+	assert.False(t, api.IntegrationPlatform.Served())
+
+	api = Resolve(capabilities.New(
+		IntegrationPlatformsV1.GroupVersionResource,
+		KameletBindingsV1Alpha1.GroupVersionResource,
+		KameletsV1Alpha1.GroupVersionResource,
+	))
+	assert.Equal(t, Version1, api.Version)
+	assert.Equal(t, IntegrationPlatformsV1, api.IntegrationPlatform)
+	assert.Equal(t, KameletBindingsV1Alpha1, api.Pipe)
+	assert.Equal(t, KameletsV1Alpha1, api.Kamelet)
+	assert.False(t, api.IntegrationProfile.Served())
+
+	api = Resolve(capabilities.New(
+		IntegrationPlatformsV1.GroupVersionResource,
+		IntegrationProfilesV1.GroupVersionResource,
+		PipesV1.GroupVersionResource,
+		KameletBindingsV1Alpha1.GroupVersionResource,
+		KameletsV1.GroupVersionResource,
+		KameletsV1Alpha1.GroupVersionResource,
+	))
+	assert.Equal(t, Version2, api.Version)
+	assert.Equal(t, PipesV1, api.Pipe)
+	assert.Equal(t, KameletsV1, api.Kamelet)
+	assert.Equal(t, IntegrationProfilesV1, api.IntegrationProfile)
+	assert.Equal(t, "camel.apache.org/v1, Kind=Pipe", api.Pipe.GroupVersionKind().String())
+}
+
+func TestEncodeDecodeIntegrationPlatform(t *testing.T) {
+	in := camelv1ac.IntegrationPlatform("p", "ns").
+		WithSpec(camelv1ac.IntegrationPlatformSpec().
+			WithBuild(camelv1ac.IntegrationPlatformBuildSpec().
+				WithBuildConfiguration(camelv1ac.BuildConfiguration().WithStrategy(camelv1.BuildStrategyPod)).
+				WithBaseImage("eclipse-temurin:17")))
+
+	v2 := API{Version: Version2, IntegrationPlatform: IntegrationPlatformsV1}
+
+	u, err := v2.EncodeIntegrationPlatform(in)
+	require.NoError(t, err)
+	assert.Equal(t, "camel.apache.org/v1", u.GetAPIVersion())
+	assert.Equal(t, KindIntegrationPlatform, u.GetKind())
+	assert.Equal(t, "p", u.GetName())
+	assert.Equal(t, "ns", u.GetNamespace())
+
+	strategy, _, _ := unstructured.NestedString(u.Object, "spec", "build", "buildConfiguration", "strategy")
+	assert.Equal(t, "pod", strategy)
+
+	v1 := API{Version: Version1, IntegrationPlatform: IntegrationPlatformsV1}
+
+	u, err = v1.EncodeIntegrationPlatform(in)
+	require.NoError(t, err)
+
+	_, found, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "build", "buildConfiguration")
+	assert.False(t, found)
+	strategy, _, _ = unstructured.NestedString(u.Object, "spec", "build", "buildStrategy")
+	assert.Equal(t, "pod", strategy)
+	image, _, _ := unstructured.NestedString(u.Object, "spec", "build", "baseImage")
+	assert.Equal(t, "eclipse-temurin:17", image)
+
+	require.NoError(t, unstructured.SetNestedField(u.Object, "routine", "status", "build", "buildStrategy"))
+	require.NoError(t, unstructured.SetNestedField(u.Object, "1.12.0", "status", "version"))
+
+	p, err := v1.DecodeIntegrationPlatform(u)
+	require.NoError(t, err)
+	assert.Equal(t, camelv1.BuildStrategyPod, p.Spec.Build.BuildConfiguration.Strategy)
+	assert.Equal(t, camelv1.BuildStrategyRoutine, p.Status.Build.BuildConfiguration.Strategy)
+	assert.Equal(t, "1.12.0", p.Status.Version)
+
+	// the input is left untouched
+	strategy, _, _ = unstructured.NestedString(u.Object, "spec", "build", "buildStrategy")
+	assert.Equal(t, "pod", strategy)
+}
+
+func TestDowngradeBuild(t *testing.T) {
+	tests := []struct {
+		name        string
+		build       map[string]interface{}
+		expected    map[string]interface{}
+		unsupported bool
+	}{
+		{
+			name:     "empty",
+			build:    map[string]interface{}{},
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "build strategy",
+			build: map[string]interface{}{
+				"buildConfiguration": map[string]interface{}{"strategy": "pod", "orderStrategy": "fifo"},
+			},
+			expected: map[string]interface{}{"buildStrategy": "pod"},
+		},
+		{
+			name:     "supported publish strategy",
+			build:    map[string]interface{}{"publishStrategy": "S2I"},
+			expected: map[string]interface{}{"publishStrategy": "S2I"},
+		},
+		{
+			name:        "unsupported publish strategy",
+			build:       map[string]interface{}{"publishStrategy": "Jib"},
+			unsupported: true,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			content := map[string]interface{}{"build": tt.build}
+
+			err := downgradeBuild(content, "build")
+			if tt.unsupported {
+				require.Error(t, err)
+				assert.True(t, IsUnsupported(err))
+				assert.Contains(t, err.Error(), `publishStrategy "Jib" is not supported by Camel K 1.x`)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, content["build"])
+		})
+	}
+}
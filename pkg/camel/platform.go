@@ -0,0 +1,119 @@
+package camel
+
+import (
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	camelv1 "github.com/apache/camel-k/v2/pkg/apis/camel/v1"
+	camelv1ac "github.com/apache/camel-k/v2/pkg/client/camel/applyconfiguration/camel/v1"
+)
+
+// publishStrategiesV1 are the publish strategies of the Workspace API accepted by
+// Camel K 1.x. Buildah and Kaniko, which were removed by 2.x, are not part of it.
+var publishStrategiesV1 = map[string]bool{
+	string(camelv1.IntegrationPlatformBuildPublishStrategyS2I):      true,
+	string(camelv1.IntegrationPlatformBuildPublishStrategySpectrum): true,
+}
+
+// UnsupportedError reports a value of the 2.x API that has no equivalent in the
+// Camel K release installed in the cluster.
+type UnsupportedError struct {
+	Version Version
+	Field   string
+	Value   string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s %q is not supported by Camel K %s", e.Field, e.Value, e.Version)
+}
+
+// IsUnsupported returns true if the given error, or one it wraps, is an UnsupportedError.
+func IsUnsupported(err error) bool {
+	var target *UnsupportedError
+	return errors.As(err, &target)
+}
+
+// EncodeIntegrationPlatform converts the given apply configuration, expressed with the
+// 2.x API, into an object matching the API served by the cluster.
+func (a API) EncodeIntegrationPlatform(in *camelv1ac.IntegrationPlatformApplyConfiguration) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(in)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := unstructured.Unstructured{Object: content}
+	answer.SetGroupVersionKind(a.IntegrationPlatform.GroupVersionKind())
+
+	if a.Version == Version1 {
+		if err := downgradeBuild(answer.Object, "spec", "build"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &answer, nil
+}
+
+// DecodeIntegrationPlatform converts an IntegrationPlatform as served by the cluster
+// into the 2.x API.
+func (a API) DecodeIntegrationPlatform(in *unstructured.Unstructured) (*camelv1.IntegrationPlatform, error) {
+	content := in.DeepCopy().Object
+
+	if a.Version == Version1 {
+		// the status embeds the spec
+		for _, fields := range [][]string{{"spec", "build"}, {"status", "build"}} {
+			if err := upgradeBuild(content, fields...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	answer := camelv1.IntegrationPlatform{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &answer); err != nil {
+		return nil, err
+	}
+
+	return &answer, nil
+}
+
+// downgradeBuild moves the build strategy from buildConfiguration.strategy, as
+// introduced by 2.x, to buildStrategy, as expected by 1.x. The other fields of
+// buildConfiguration have no 1.x equivalent and are dropped. Publish strategies
+// introduced by 2.x, such as Jib, are rejected with an UnsupportedError.
+func downgradeBuild(content map[string]interface{}, fields ...string) error {
+	publish, found, err := unstructured.NestedString(content, append(fields, "publishStrategy")...)
+	if err != nil {
+		return err
+	}
+
+	if found && !publishStrategiesV1[publish] {
+		return &UnsupportedError{Version: Version1, Field: "publishStrategy", Value: publish}
+	}
+
+	strategy, found, err := unstructured.NestedString(content, append(fields, "buildConfiguration", "strategy")...)
+	if err != nil {
+		return err
+	}
+
+	unstructured.RemoveNestedField(content, append(fields, "buildConfiguration")...)
+
+	if !found {
+		return nil
+	}
+
+	return unstructured.SetNestedField(content, strategy, append(fields, "buildStrategy")...)
+}
+
+// upgradeBuild is the inverse of downgradeBuild.
+func upgradeBuild(content map[string]interface{}, fields ...string) error {
+	strategy, found, err := unstructured.NestedString(content, append(fields, "buildStrategy")...)
+	if err != nil || !found {
+		return err
+	}
+
+	unstructured.RemoveNestedField(content, append(fields, "buildStrategy")...)
+
+	return unstructured.SetNestedField(content, strategy, append(fields, "buildConfiguration", "strategy")...)
+}
@@ -0,0 +1,181 @@
+package capabilities
+
+import (
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Capability identifies a set of APIs the operator can make use of when they are
+// served by the cluster.
+type Capability string
+
+const (
+	Routes          Capability = "Routes"
+	Ingress         Capability = "Ingress"
+	GatewayAPI      Capability = "GatewayAPI"
+	ServiceMonitor  Capability = "ServiceMonitor"
+	KnativeServing  Capability = "KnativeServing"
+	KnativeEventing Capability = "KnativeEventing"
+	CamelK          Capability = "CamelK"
+	KEDA            Capability = "KEDA"
+
+	CamelKGroup     = "camel.apache.org"
+	GatewayAPIGroup = "gateway.networking.k8s.io"
+)
+
+// resources lists, for each capability, the resources whose presence denotes
+// the capability. Any of them is enough.
+var resources = map[Capability][]schema.GroupVersionResource{
+	Routes: {
+		{Group: "route.openshift.io", Version: "v1", Resource: "routes"},
+	},
+	Ingress: {
+		{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+	},
+	GatewayAPI: {
+		{Group: GatewayAPIGroup, Version: "v1", Resource: "httproutes"},
+		{Group: GatewayAPIGroup, Version: "v1beta1", Resource: "httproutes"},
+	},
+	ServiceMonitor: {
+		{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"},
+	},
+	KnativeServing: {
+		{Group: "serving.knative.dev", Version: "v1", Resource: "services"},
+	},
+	KnativeEventing: {
+		{Group: "eventing.knative.dev", Version: "v1", Resource: "brokers"},
+	},
+	CamelK: {
+		{Group: CamelKGroup, Version: "v1", Resource: "integrationplatforms"},
+	},
+	KEDA: {
+		{Group: "keda.sh", Version: "v1alpha1", Resource: "scaledobjects"},
+	},
+}
+
+// All returns all the known capabilities, sorted by name.
+func All() []Capability {
+	answer := make([]Capability, 0, len(resources))
+	for c := range resources {
+		answer = append(answer, c)
+	}
+
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i] < answer[j]
+	})
+
+	return answer
+}
+
+// Groups returns the API groups involved in the known capabilities.
+func Groups() sets.Set[string] {
+	answer := sets.New[string]()
+	for _, gvrs := range resources {
+		for _, gvr := range gvrs {
+			answer.Insert(gvr.Group)
+		}
+	}
+
+	return answer
+}
+
+// Describe returns a human readable list of the resources denoting the given capability.
+func Describe(capability Capability) string {
+	items := make([]string, 0, len(resources[capability]))
+	for _, gvr := range resources[capability] {
+		items = append(items, gvr.GroupVersion().String()+" "+gvr.Resource)
+	}
+
+	return strings.Join(items, ", ")
+}
+
+// Capabilities is an immutable snapshot of the APIs served by a cluster.
+type Capabilities struct {
+	resources map[schema.GroupVersion]sets.Set[string]
+}
+
+// New returns the capabilities corresponding to the given served resources.
+func New(served ...schema.GroupVersionResource) Capabilities {
+	answer := Capabilities{
+		resources: make(map[schema.GroupVersion]sets.Set[string]),
+	}
+
+	for _, gvr := range served {
+		gv := gvr.GroupVersion()
+
+		if _, ok := answer.resources[gv]; !ok {
+			answer.resources[gv] = sets.New[string]()
+		}
+
+		answer.resources[gv].Insert(gvr.Resource)
+	}
+
+	return answer
+}
+
+// Has returns true if the given capability is available.
+func (c Capabilities) Has(capability Capability) bool {
+	for _, gvr := range resources[capability] {
+		if c.HasResource(gvr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasResource returns true if the given resource is served.
+func (c Capabilities) HasResource(gvr schema.GroupVersionResource) bool {
+	return c.resources[gvr.GroupVersion()].Has(gvr.Resource)
+}
+
+// Versions returns the versions served for the given API group, sorted.
+func (c Capabilities) Versions(group string) []string {
+	answer := make([]string, 0)
+
+	for gv := range c.resources {
+		if gv.Group == group {
+			answer = append(answer, gv.Version)
+		}
+	}
+
+	sort.Strings(answer)
+
+	return answer
+}
+
+// Missing returns the capabilities among the given ones that are not available.
+func (c Capabilities) Missing(capabilities ...Capability) []Capability {
+	answer := make([]Capability, 0)
+
+	for _, capability := range capabilities {
+		if !c.Has(capability) {
+			answer = append(answer, capability)
+		}
+	}
+
+	return answer
+}
+
+// IsOpenShift returns true if the cluster looks like an OpenShift cluster.
+func (c Capabilities) IsOpenShift() bool {
+	return c.Has(Routes)
+}
+
+// Equal returns true if both snapshots hold the same resources.
+func (c Capabilities) Equal(other Capabilities) bool {
+	if len(c.resources) != len(other.resources) {
+		return false
+	}
+
+	for gv, r := range c.resources {
+		if !r.Equal(other.resources[gv]) {
+			return false
+		}
+	}
+
+	return true
+}
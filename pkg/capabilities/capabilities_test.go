@@ -0,0 +1,188 @@
+package capabilities
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// blockingDiscovery returns the resources sent through its channel, so that the
+// tests control when a discovery completes.
+type blockingDiscovery struct {
+	*fakediscovery.FakeDiscovery
+	results chan []*metav1.APIResourceList
+}
+
+func (d *blockingDiscovery) ServerGroupsAndResources() ([]*metav1.APIGroup, []*metav1.APIResourceList, error) {
+	return nil, <-d.results, nil
+}
+
+func newDiscovery(lists ...*metav1.APIResourceList) *fakediscovery.FakeDiscovery {
+	return &fakediscovery.FakeDiscovery{
+		Fake: &clienttesting.Fake{
+			Resources: lists,
+		},
+	}
+}
+
+func resourceList(gv string, resources ...string) *metav1.APIResourceList {
+	answer := metav1.APIResourceList{
+		GroupVersion: gv,
+	}
+
+	for _, r := range resources {
+		answer.APIResources = append(answer.APIResources, metav1.APIResource{Name: r})
+	}
+
+	return &answer
+}
+
+func TestDiscover(t *testing.T) {
+	d := newDiscovery(
+		resourceList("v1", "pods", "services"),
+		resourceList("route.openshift.io/v1", "routes"),
+		resourceList("gateway.networking.k8s.io/v1beta1", "gateways", "httproutes"),
+		resourceList("camel.apache.org/v1", "integrationplatforms", "integrations"),
+		resourceList("camel.apache.org/v1alpha1", "kameletbindings"),
+	)
+
+	c, err := Discover(d)
+	require.NoError(t, err)
+
+	assert.True(t, c.Has(Routes))
+	assert.True(t, c.IsOpenShift())
+	assert.True(t, c.Has(GatewayAPI))
+	assert.True(t, c.Has(CamelK))
+	assert.False(t, c.Has(Ingress))
+	assert.False(t, c.Has(KEDA))
+	assert.Equal(t, []string{"v1", "v1alpha1"}, c.Versions(CamelKGroup))
+	assert.Equal(t, []Capability{Ingress, KEDA}, c.Missing(CamelK, Ingress, Routes, KEDA))
+}
+
+func TestService(t *testing.T) {
+	d := newDiscovery(
+		resourceList("networking.k8s.io/v1", "ingresses"),
+	)
+
+	s := NewService(d)
+
+	assert.False(t, s.Get().Has(Ingress))
+
+	changed, err := s.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, s.Get().Has(Ingress))
+
+	changed, err = s.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	d.Resources = append(d.Resources, resourceList("camel.apache.org/v1", "integrationplatforms"))
+
+	changed, err = s.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, s.Get().Has(CamelK))
+}
+
+func TestServiceOnChange(t *testing.T) {
+	d := newDiscovery()
+	s := NewService(d)
+
+	notified := make([]Capabilities, 0)
+	s.OnChange(func(c Capabilities) {
+		notified = append(notified, c)
+	})
+
+	_, err := s.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, notified)
+
+	d.Resources = append(d.Resources, resourceList("camel.apache.org/v1", "integrationplatforms"))
+
+	_, err = s.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Len(t, notified, 1)
+	assert.True(t, notified[0].Has(CamelK))
+}
+
+func TestServiceConcurrentRefresh(t *testing.T) {
+	d := blockingDiscovery{
+		FakeDiscovery: newDiscovery(),
+		results:       make(chan []*metav1.APIResourceList),
+	}
+
+	s := NewService(&d)
+
+	notified := make([]Capabilities, 0)
+	s.OnChange(func(c Capabilities) {
+		notified = append(notified, c)
+	})
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := s.Refresh(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+
+	// the older snapshot is discovered first, it must not be stored last
+	d.results <- []*metav1.APIResourceList{}
+	d.results <- []*metav1.APIResourceList{resourceList("camel.apache.org/v1", "integrationplatforms")}
+
+	wg.Wait()
+
+	assert.True(t, s.Get().Has(CamelK))
+	require.Len(t, notified, 1)
+	assert.True(t, notified[0].Has(CamelK))
+}
+
+func TestServiceRefreshInterval(t *testing.T) {
+	d := blockingDiscovery{
+		FakeDiscovery: newDiscovery(),
+		results:       make(chan []*metav1.APIResourceList),
+	}
+
+	s := NewService(&d, WithRefreshInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// unblocks the pending refreshes
+	defer close(d.results)
+
+	go func() {
+		assert.NoError(t, s.Start(ctx))
+	}()
+
+	// the new interval applies without restarting the service
+	s.SetRefreshInterval(10 * time.Millisecond)
+
+	select {
+	case d.results <- []*metav1.APIResourceList{resourceList("camel.apache.org/v1", "integrationplatforms")}:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "the capabilities have not been refreshed")
+	}
+
+	require.Eventually(t, func() bool {
+		return s.Get().Has(CamelK)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "gateway.networking.k8s.io/v1 httproutes, gateway.networking.k8s.io/v1beta1 httproutes", Describe(GatewayAPI))
+	assert.True(t, Groups().Has(CamelKGroup))
+}
@@ -0,0 +1,199 @@
+package capabilities
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+const DefaultRefreshInterval = 5 * time.Minute
+
+type Option func(*Service)
+
+// WithRefreshInterval sets how often the capabilities are discovered again.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		s.interval = interval
+	}
+}
+
+// WithLogger sets the logger used to report capability changes.
+func WithLogger(l logr.Logger) Option {
+	return func(s *Service) {
+		s.l = l
+	}
+}
+
+// Service discovers and caches the capabilities of the cluster, refreshing them
+// periodically once started. It implements manager.Runnable.
+type Service struct {
+	discovery discovery.DiscoveryInterface
+	l         logr.Logger
+
+	// reset notifies Start that the refresh interval has changed
+	reset chan struct{}
+
+	// refresh serializes the refreshes, so that a slow discovery can't override
+	// the outcome of a more recent one
+	refresh sync.Mutex
+
+	lock      sync.RWMutex
+	interval  time.Duration
+	current   Capabilities
+	listeners []func(Capabilities)
+}
+
+func NewService(d discovery.DiscoveryInterface, opts ...Option) *Service {
+	s := Service{
+		discovery: d,
+		interval:  DefaultRefreshInterval,
+		l:         logr.Discard(),
+		current:   New(),
+		reset:     make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return &s
+}
+
+// Get returns the last discovered capabilities.
+func (s *Service) Get() Capabilities {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.current
+}
+
+// OnChange registers a function invoked with the new capabilities every time
+// a refresh detects a change.
+func (s *Service) OnChange(listener func(Capabilities)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// SetRefreshInterval changes how often the capabilities are discovered again, the
+// default interval is used if the given one is not positive. The next refresh is
+// scheduled one interval after the change.
+func (s *Service) SetRefreshInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+
+	s.lock.Lock()
+	changed := s.interval != interval
+	s.interval = interval
+	s.lock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	// a pending notification is enough as the interval is read on reset
+	select {
+	case s.reset <- struct{}{}:
+	default:
+	}
+}
+
+// RefreshInterval returns how often the capabilities are discovered again.
+func (s *Service) RefreshInterval() time.Duration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.interval
+}
+
+// Refresh discovers the capabilities of the cluster and returns true if they
+// have changed since the last refresh. Concurrent refreshes are serialized, hence
+// listeners must not refresh the service.
+func (s *Service) Refresh(_ context.Context) (bool, error) {
+	s.refresh.Lock()
+	defer s.refresh.Unlock()
+
+	c, err := Discover(s.discovery)
+	if err != nil {
+		return false, err
+	}
+
+	s.lock.Lock()
+
+	if s.current.Equal(c) {
+		s.lock.Unlock()
+		return false, nil
+	}
+
+	for _, capability := range All() {
+		if before, after := s.current.Has(capability), c.Has(capability); before != after {
+			s.l.Info("Capability changed", "capability", capability, "available", after)
+		}
+	}
+
+	s.current = c
+	listeners := s.listeners
+
+	s.lock.Unlock()
+
+	// listeners are notified without holding the lock so they can use the service
+	for _, listener := range listeners {
+		listener(c)
+	}
+
+	return true, nil
+}
+
+// Start refreshes the capabilities until the context is done.
+func (s *Service) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.RefreshInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.reset:
+			ticker.Reset(s.RefreshInterval())
+		case <-ticker.C:
+			if _, err := s.Refresh(ctx); err != nil {
+				s.l.Error(err, "unable to refresh capabilities")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false as the capabilities are required by all the replicas.
+func (s *Service) NeedLeaderElection() bool {
+	return false
+}
+
+// Discover returns the capabilities of the cluster. Groups that fail to be
+// discovered, e.g. because of an unavailable aggregated API, are ignored.
+func Discover(d discovery.DiscoveryInterface) (Capabilities, error) {
+	_, lists, err := d.ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return Capabilities{}, err
+	}
+
+	served := make([]schema.GroupVersionResource, 0)
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			return Capabilities{}, err
+		}
+
+		for _, r := range list.APIResources {
+			served = append(served, gv.WithResource(r.Name))
+		}
+	}
+
+	return New(served...), nil
+}
@@ -18,7 +18,6 @@ limitations under the License.
 package v1alpha1
 
 import (
-	v1alpha1 "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
 	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
@@ -29,7 +28,7 @@ import (
 type WorkspaceApplyConfiguration struct {
 	v1.TypeMetaApplyConfiguration    `json:",inline"`
 	*v1.ObjectMetaApplyConfiguration `json:"metadata,omitempty"`
-	Spec                             *v1alpha1.WorkspaceSpec            `json:"spec,omitempty"`
+	Spec                             *WorkspaceSpecApplyConfiguration   `json:"spec,omitempty"`
 	Status                           *WorkspaceStatusApplyConfiguration `json:"status,omitempty"`
 }
 
@@ -205,8 +204,8 @@ func (b *WorkspaceApplyConfiguration) ensureObjectMetaApplyConfigurationExists()
 // WithSpec sets the Spec field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Spec field is set to the value of the last call.
-func (b *WorkspaceApplyConfiguration) WithSpec(value v1alpha1.WorkspaceSpec) *WorkspaceApplyConfiguration {
-	b.Spec = &value
+func (b *WorkspaceApplyConfiguration) WithSpec(value *WorkspaceSpecApplyConfiguration) *WorkspaceApplyConfiguration {
+	b.Spec = value
 	return b
 }
 
@@ -18,16 +18,21 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1alpha1 "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // WorkspaceStatusApplyConfiguration represents an declarative configuration of the WorkspaceStatus type for use
 // with apply.
 type WorkspaceStatusApplyConfiguration struct {
-	Phase              *string        `json:"phase,omitempty"`
-	Conditions         []v1.Condition `json:"conditions,omitempty"`
-	ObservedGeneration *int64         `json:"observedGeneration,omitempty"`
-	Endpoint           *string        `json:"endpoint,omitempty"`
+	Phase              *v1alpha1.WorkspacePhase              `json:"phase,omitempty"`
+	LastTransitionTime *v1.Time                              `json:"lastTransitionTime,omitempty"`
+	Conditions         []v1.Condition                        `json:"conditions,omitempty"`
+	ObservedGeneration *int64                                `json:"observedGeneration,omitempty"`
+	Endpoint           *string                               `json:"endpoint,omitempty"`
+	Orphaned           []ResourceReferenceApplyConfiguration `json:"orphaned,omitempty"`
+	CamelK             *CamelKStatusApplyConfiguration       `json:"camelK,omitempty"`
+	Profiles           []ProfileStatusApplyConfiguration     `json:"profiles,omitempty"`
 }
 
 // WorkspaceStatusApplyConfiguration constructs an declarative configuration of the WorkspaceStatus type for use with
@@ -39,11 +44,19 @@ func WorkspaceStatus() *WorkspaceStatusApplyConfiguration {
 // WithPhase sets the Phase field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Phase field is set to the value of the last call.
-func (b *WorkspaceStatusApplyConfiguration) WithPhase(value string) *WorkspaceStatusApplyConfiguration {
+func (b *WorkspaceStatusApplyConfiguration) WithPhase(value v1alpha1.WorkspacePhase) *WorkspaceStatusApplyConfiguration {
 	b.Phase = &value
 	return b
 }
 
+// WithLastTransitionTime sets the LastTransitionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastTransitionTime field is set to the value of the last call.
+func (b *WorkspaceStatusApplyConfiguration) WithLastTransitionTime(value v1.Time) *WorkspaceStatusApplyConfiguration {
+	b.LastTransitionTime = &value
+	return b
+}
+
 // WithConditions adds the given value to the Conditions field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the Conditions field.
@@ -69,3 +82,37 @@ func (b *WorkspaceStatusApplyConfiguration) WithEndpoint(value string) *Workspac
 	b.Endpoint = &value
 	return b
 }
+
+// WithOrphaned adds the given value to the Orphaned field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Orphaned field.
+func (b *WorkspaceStatusApplyConfiguration) WithOrphaned(values ...*ResourceReferenceApplyConfiguration) *WorkspaceStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithOrphaned")
+		}
+		b.Orphaned = append(b.Orphaned, *values[i])
+	}
+	return b
+}
+
+// WithCamelK sets the CamelK field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CamelK field is set to the value of the last call.
+func (b *WorkspaceStatusApplyConfiguration) WithCamelK(value *CamelKStatusApplyConfiguration) *WorkspaceStatusApplyConfiguration {
+	b.CamelK = value
+	return b
+}
+
+// WithProfiles adds the given value to the Profiles field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Profiles field.
+func (b *WorkspaceStatusApplyConfiguration) WithProfiles(values ...*ProfileStatusApplyConfiguration) *WorkspaceStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithProfiles")
+		}
+		b.Profiles = append(b.Profiles, *values[i])
+	}
+	return b
+}
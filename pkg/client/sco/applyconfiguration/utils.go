@@ -19,7 +19,9 @@ package applyconfiguration
 
 import (
 	v1alpha1 "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	v1beta1 "github.com/sco1237896/sco-operator/api/sco/v1beta1"
 	scov1alpha1 "github.com/sco1237896/sco-operator/pkg/client/sco/applyconfiguration/sco/v1alpha1"
+	scov1beta1 "github.com/sco1237896/sco-operator/pkg/client/sco/applyconfiguration/sco/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -28,11 +30,69 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=sco, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("BuildSpec"):
+		return &scov1alpha1.BuildSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("CamelKStatus"):
+		return &scov1alpha1.CamelKStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ExposeSpec"):
+		return &scov1alpha1.ExposeSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("GatewayReference"):
+		return &scov1alpha1.GatewayReferenceApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KameletSpec"):
+		return &scov1alpha1.KameletSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("PlatformSpec"):
+		return &scov1alpha1.PlatformSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ProfileBuildSpec"):
+		return &scov1alpha1.ProfileBuildSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ProfileSpec"):
+		return &scov1alpha1.ProfileSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ProfileStatus"):
+		return &scov1alpha1.ProfileStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RegistrySpec"):
+		return &scov1alpha1.RegistrySpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ResourceReference"):
+		return &scov1alpha1.ResourceReferenceApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("TLSSpec"):
+		return &scov1alpha1.TLSSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Workspace"):
 		return &scov1alpha1.WorkspaceApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("WorkspaceSpec"):
+		return &scov1alpha1.WorkspaceSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("WorkspaceStatus"):
 		return &scov1alpha1.WorkspaceStatusApplyConfiguration{}
 
+		// Group=sco, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("BuildSpec"):
+		return &scov1beta1.BuildSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("CamelKStatus"):
+		return &scov1beta1.CamelKStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ExposeSpec"):
+		return &scov1beta1.ExposeSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("GatewayReference"):
+		return &scov1beta1.GatewayReferenceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("KameletSpec"):
+		return &scov1beta1.KameletSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("PlatformSpec"):
+		return &scov1beta1.PlatformSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ProfileBuildSpec"):
+		return &scov1beta1.ProfileBuildSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ProfileSpec"):
+		return &scov1beta1.ProfileSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ProfileStatus"):
+		return &scov1beta1.ProfileStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RegistrySpec"):
+		return &scov1beta1.RegistrySpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("ResourceReference"):
+		return &scov1beta1.ResourceReferenceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("TLSSpec"):
+		return &scov1beta1.TLSSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("Workspace"):
+		return &scov1beta1.WorkspaceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("WorkspaceSpec"):
+		return &scov1beta1.WorkspaceSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("WorkspaceStatus"):
+		return &scov1beta1.WorkspaceStatusApplyConfiguration{}
+
 	}
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	scov1alpha1 "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned/typed/sco/v1alpha1"
+	scov1beta1 "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned/typed/sco/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,12 +31,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	ScoV1alpha1() scov1alpha1.ScoV1alpha1Interface
+	ScoV1beta1() scov1beta1.ScoV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	scoV1alpha1 *scov1alpha1.ScoV1alpha1Client
+	scoV1beta1  *scov1beta1.ScoV1beta1Client
 }
 
 // ScoV1alpha1 retrieves the ScoV1alpha1Client
@@ -43,6 +46,11 @@ func (c *Clientset) ScoV1alpha1() scov1alpha1.ScoV1alpha1Interface {
 	return c.scoV1alpha1
 }
 
+// ScoV1beta1 retrieves the ScoV1beta1Client
+func (c *Clientset) ScoV1beta1() scov1beta1.ScoV1beta1Interface {
+	return c.scoV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -91,6 +99,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.scoV1beta1, err = scov1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -113,6 +125,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.scoV1alpha1 = scov1alpha1.New(c)
+	cs.scoV1beta1 = scov1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -21,6 +21,8 @@ import (
 	clientset "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned"
 	scov1alpha1 "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned/typed/sco/v1alpha1"
 	fakescov1alpha1 "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned/typed/sco/v1alpha1/fake"
+	scov1beta1 "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned/typed/sco/v1beta1"
+	fakescov1beta1 "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned/typed/sco/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -82,3 +84,8 @@ var (
 func (c *Clientset) ScoV1alpha1() scov1alpha1.ScoV1alpha1Interface {
 	return &fakescov1alpha1.FakeScoV1alpha1{Fake: &c.Fake}
 }
+
+// ScoV1beta1 retrieves the ScoV1beta1Client
+func (c *Clientset) ScoV1beta1() scov1beta1.ScoV1beta1Interface {
+	return &fakescov1beta1.FakeScoV1beta1{Fake: &c.Fake}
+}
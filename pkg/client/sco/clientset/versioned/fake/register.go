@@ -19,6 +19,7 @@ package fake
 
 import (
 	scov1alpha1 "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	scov1beta1 "github.com/sco1237896/sco-operator/api/sco/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -31,6 +32,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	scov1alpha1.AddToScheme,
+	scov1beta1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/sco1237896/sco-operator/api/sco/v1alpha1"
+	v1beta1 "github.com/sco1237896/sco-operator/api/sco/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -55,6 +56,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1alpha1.SchemeGroupVersion.WithResource("workspaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Sco().V1alpha1().Workspaces().Informer()}, nil
 
+		// Group=sco, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("workspaces"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Sco().V1beta1().Workspaces().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
@@ -1,6 +1,10 @@
 package conditions
 
 import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -18,6 +22,15 @@ type Getter interface {
 	GetConditions() Conditions
 }
 
+// Setter interface defines methods that an object should implement in order to
+// use the conditions package for setting conditions.
+type Setter interface {
+	Getter
+
+	// SetConditions sets the list of conditions for an object.
+	SetConditions(Conditions)
+}
+
 type Conditions []metav1.Condition
 
 // Get returns the condition with the given type, if the condition does not exist,
@@ -35,3 +48,91 @@ func Get(from Getter, t ConditionType) *metav1.Condition {
 	}
 	return nil
 }
+
+// Has returns true if a condition with the given type exists.
+func Has(from Getter, t ConditionType) bool {
+	return Get(from, t) != nil
+}
+
+// IsTrue is true if the condition with the given type is True, otherwise it returns false
+// if the condition is not True or if the condition does not exist.
+func IsTrue(from Getter, t ConditionType) bool {
+	if c := Get(from, t); c != nil {
+		return c.Status == metav1.ConditionTrue
+	}
+	return false
+}
+
+// IsFalse is true if the condition with the given type is False, otherwise it returns false
+// if the condition is not False or if the condition does not exist.
+func IsFalse(from Getter, t ConditionType) bool {
+	if c := Get(from, t); c != nil {
+		return c.Status == metav1.ConditionFalse
+	}
+	return false
+}
+
+// IsUnknown is true if the condition with the given type is Unknown or if the condition
+// does not exist.
+func IsUnknown(from Getter, t ConditionType) bool {
+	if c := Get(from, t); c != nil {
+		return c.Status == metav1.ConditionUnknown
+	}
+	return true
+}
+
+// Set sets the given condition, the LastTransitionTime is updated only if the status
+// of the condition changes. Conditions are kept sorted by type so that the order is
+// stable across updates.
+func Set(to Setter, condition metav1.Condition) {
+	conditions := to.GetConditions()
+
+	meta.SetStatusCondition((*[]metav1.Condition)(&conditions), condition)
+
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return conditions[i].Type < conditions[j].Type
+	})
+
+	to.SetConditions(conditions)
+}
+
+// MarkTrue sets the condition with the given type to True.
+func MarkTrue(to Setter, t ConditionType, reason string, messageFormat string, messageArgs ...interface{}) {
+	Set(to, newCondition(to, t, metav1.ConditionTrue, reason, messageFormat, messageArgs...))
+}
+
+// MarkFalse sets the condition with the given type to False.
+func MarkFalse(to Setter, t ConditionType, reason string, messageFormat string, messageArgs ...interface{}) {
+	Set(to, newCondition(to, t, metav1.ConditionFalse, reason, messageFormat, messageArgs...))
+}
+
+// MarkUnknown sets the condition with the given type to Unknown.
+func MarkUnknown(to Setter, t ConditionType, reason string, messageFormat string, messageArgs ...interface{}) {
+	Set(to, newCondition(to, t, metav1.ConditionUnknown, reason, messageFormat, messageArgs...))
+}
+
+// Delete deletes the condition with the given type.
+func Delete(to Setter, t ConditionType) {
+	conditions := to.GetConditions()
+
+	meta.RemoveStatusCondition((*[]metav1.Condition)(&conditions), string(t))
+
+	to.SetConditions(conditions)
+}
+
+func newCondition(
+	from Getter,
+	t ConditionType,
+	status metav1.ConditionStatus,
+	reason string,
+	messageFormat string,
+	messageArgs ...interface{},
+) metav1.Condition {
+	return metav1.Condition{
+		Type:               string(t),
+		Status:             status,
+		Reason:             reason,
+		Message:            fmt.Sprintf(messageFormat, messageArgs...),
+		ObservedGeneration: from.GetGeneration(),
+	}
+}
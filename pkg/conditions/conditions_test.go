@@ -0,0 +1,62 @@
+package conditions
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type setter struct {
+	getter
+}
+
+func (s *setter) SetConditions(conditions Conditions) {
+	s.conditions = conditions
+}
+
+func TestSetAndMark(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	s := setter{}
+	s.Generation = 2
+
+	MarkTrue(&s, "B", "Ok", "all %s", "good")
+	MarkFalse(&s, "A", "Fail", "failure")
+	MarkUnknown(&s, "C", "Unknown", "unknown")
+
+	g.Expect(s.GetConditions()).To(MatchConditions(Conditions{
+		{Type: "A", Status: metav1.ConditionFalse, Reason: "Fail", Message: "failure", ObservedGeneration: 2},
+		{Type: "B", Status: metav1.ConditionTrue, Reason: "Ok", Message: "all good", ObservedGeneration: 2},
+		{Type: "C", Status: metav1.ConditionUnknown, Reason: "Unknown", Message: "unknown", ObservedGeneration: 2},
+	}))
+
+	// conditions are kept sorted by type
+	g.Expect(s.GetConditions()[0].Type).To(gomega.Equal("A"))
+	g.Expect(s.GetConditions()[1].Type).To(gomega.Equal("B"))
+	g.Expect(s.GetConditions()[2].Type).To(gomega.Equal("C"))
+
+	g.Expect(Has(&s, "A")).To(gomega.BeTrue())
+	g.Expect(Has(&s, "D")).To(gomega.BeFalse())
+	g.Expect(IsTrue(&s, "B")).To(gomega.BeTrue())
+	g.Expect(IsFalse(&s, "A")).To(gomega.BeTrue())
+	g.Expect(IsUnknown(&s, "C")).To(gomega.BeTrue())
+	g.Expect(IsUnknown(&s, "D")).To(gomega.BeTrue())
+
+	transition := Get(&s, "A").LastTransitionTime
+
+	// the transition time changes only when the status changes
+	MarkFalse(&s, "A", "StillFailing", "failure")
+	g.Expect(Get(&s, "A").LastTransitionTime).To(gomega.Equal(transition))
+	g.Expect(Get(&s, "A")).To(MatchCondition(metav1.Condition{
+		Type:               "A",
+		Status:             metav1.ConditionFalse,
+		Reason:             "StillFailing",
+		Message:            "failure",
+		ObservedGeneration: 2,
+	}))
+
+	Delete(&s, "A")
+	g.Expect(Has(&s, "A")).To(gomega.BeFalse())
+	g.Expect(s.GetConditions()).To(gomega.HaveLen(2))
+}
@@ -0,0 +1,80 @@
+package conditions
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MatchConditions returns a custom matcher to check equality of Conditions, ignoring
+// the LastTransitionTime.
+func MatchConditions(expected Conditions) types.GomegaMatcher {
+	return &matchConditions{
+		expected: expected,
+	}
+}
+
+type matchConditions struct {
+	expected Conditions
+}
+
+func (m matchConditions) Match(actual interface{}) (bool, error) {
+	elems := make([]interface{}, 0, len(m.expected))
+	for _, condition := range m.expected {
+		elems = append(elems, MatchCondition(condition))
+	}
+
+	return gomega.ConsistOf(elems...).Match(actual)
+}
+
+func (m matchConditions) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected\n\t%#v\nto match\n\t%#v\n", actual, m.expected)
+}
+
+func (m matchConditions) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected\n\t%#v\nto not match\n\t%#v\n", actual, m.expected)
+}
+
+// MatchCondition returns a custom matcher to check equality of a metav1.Condition,
+// ignoring the LastTransitionTime.
+func MatchCondition(expected metav1.Condition) types.GomegaMatcher {
+	return &matchCondition{
+		expected: expected,
+	}
+}
+
+type matchCondition struct {
+	expected metav1.Condition
+}
+
+func (m matchCondition) Match(actual interface{}) (bool, error) {
+	var c metav1.Condition
+
+	switch a := actual.(type) {
+	case metav1.Condition:
+		c = a
+	case *metav1.Condition:
+		if a == nil {
+			return false, nil
+		}
+		c = *a
+	default:
+		return false, fmt.Errorf("actual should be of type metav1.Condition, got %T", actual)
+	}
+
+	return c.Type == m.expected.Type &&
+		c.Status == m.expected.Status &&
+		c.Reason == m.expected.Reason &&
+		c.Message == m.expected.Message &&
+		c.ObservedGeneration == m.expected.ObservedGeneration, nil
+}
+
+func (m matchCondition) FailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected\n\t%#v\nto match\n\t%#v\n", actual, m.expected)
+}
+
+func (m matchCondition) NegatedFailureMessage(actual interface{}) string {
+	return fmt.Sprintf("expected\n\t%#v\nto not match\n\t%#v\n", actual, m.expected)
+}
@@ -0,0 +1,156 @@
+package conditions
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Severity expresses how much a condition that is not in its good state affects the
+// summary condition.
+type Severity string
+
+const (
+	SeverityError   Severity = "Error"
+	SeverityWarning Severity = "Warning"
+	SeverityInfo    Severity = "Info"
+)
+
+var severityRank = map[Severity]int{
+	SeverityError:   3,
+	SeverityWarning: 2,
+	SeverityInfo:    1,
+}
+
+type summaryOptions struct {
+	conditions []ConditionType
+	negative   map[ConditionType]bool
+	severity   map[ConditionType]Severity
+}
+
+// SummaryOption is some configuration that modifies how the summary is computed.
+type SummaryOption func(*summaryOptions)
+
+// WithConditions restricts the summary to the given condition types, by default all
+// the conditions but the summary one are considered.
+func WithConditions(types ...ConditionType) SummaryOption {
+	return func(o *summaryOptions) {
+		o.conditions = types
+	}
+}
+
+// WithNegativePolarityConditions declares the condition types for which False is the
+// good state (e.g. Paused, Degraded).
+func WithNegativePolarityConditions(types ...ConditionType) SummaryOption {
+	return func(o *summaryOptions) {
+		for _, t := range types {
+			o.negative[t] = true
+		}
+	}
+}
+
+// WithSeverity sets the severity of the given condition types, the default is SeverityError.
+func WithSeverity(severity Severity, types ...ConditionType) SummaryOption {
+	return func(o *summaryOptions) {
+		for _, t := range types {
+			o.severity[t] = severity
+		}
+	}
+}
+
+// Summary computes a condition of the given type out of the conditions of the given
+// object:
+//
+//   - True if all the considered conditions are in their good state
+//   - False if at least one of the considered conditions is in its bad state
+//   - Unknown if no condition is in its bad state but at least one is Unknown
+//
+// When the summary is not True, the reason and the message are taken from the most
+// severe condition in its bad state, or the most severe Unknown one if none is bad;
+// ties are broken by the order of the conditions.
+func Summary(from Getter, t ConditionType, options ...SummaryOption) metav1.Condition {
+	o := summaryOptions{
+		negative: map[ConditionType]bool{},
+		severity: map[ConditionType]Severity{},
+	}
+
+	for _, option := range options {
+		option(&o)
+	}
+
+	summary := metav1.Condition{
+		Type:               string(t),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(t),
+		Message:            string(t),
+		ObservedGeneration: from.GetGeneration(),
+	}
+
+	var worst *metav1.Condition
+	worstRank := 0
+	bad := false
+
+	for _, c := range considered(from, t, o.conditions) {
+		ct := ConditionType(c.Type)
+
+		good := metav1.ConditionTrue
+		if o.negative[ct] {
+			good = metav1.ConditionFalse
+		}
+
+		if c.Status == good {
+			continue
+		}
+
+		severity, ok := o.severity[ct]
+		if !ok {
+			severity = SeverityError
+		}
+
+		rank := severityRank[severity]
+		isBad := c.Status != metav1.ConditionUnknown
+
+		// a bad state always weights more than an unknown one, whatever the severity
+		switch {
+		case isBad && !bad:
+			worst, worstRank, bad = c, rank, true
+		case isBad == bad && rank > worstRank:
+			worst, worstRank = c, rank
+		}
+	}
+
+	if worst == nil {
+		return summary
+	}
+
+	summary.Status = metav1.ConditionUnknown
+	if bad {
+		summary.Status = metav1.ConditionFalse
+	}
+
+	summary.Reason = worst.Reason
+	summary.Message = worst.Type + ": " + worst.Message
+
+	return summary
+}
+
+func considered(from Getter, t ConditionType, types []ConditionType) []*metav1.Condition {
+	answer := make([]*metav1.Condition, 0)
+
+	if len(types) == 0 {
+		conditions := from.GetConditions()
+		for i := range conditions {
+			if conditions[i].Type != string(t) {
+				answer = append(answer, &conditions[i])
+			}
+		}
+
+		return answer
+	}
+
+	for _, ct := range types {
+		if c := Get(from, ct); c != nil {
+			answer = append(answer, c)
+		}
+	}
+
+	return answer
+}
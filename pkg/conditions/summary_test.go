@@ -0,0 +1,128 @@
+package conditions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type getter struct {
+	corev1.ConfigMap
+	conditions Conditions
+}
+
+func (g *getter) GetConditions() Conditions {
+	return g.conditions
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions Conditions
+		options    []SummaryOption
+		status     metav1.ConditionStatus
+		reason     string
+		message    string
+	}{
+		{
+			name:   "empty",
+			status: metav1.ConditionTrue,
+			reason: "Ready",
+		},
+		{
+			name: "all good",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionTrue, Reason: "Ok"},
+				{Type: "Paused", Status: metav1.ConditionFalse, Reason: "Resumed"},
+				{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Stale"},
+			},
+			options: []SummaryOption{WithNegativePolarityConditions("Paused")},
+			status:  metav1.ConditionTrue,
+			reason:  "Ready",
+		},
+		{
+			name: "most severe wins",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionFalse, Reason: "Warn", Message: "warning"},
+				{Type: "B", Status: metav1.ConditionFalse, Reason: "Fail", Message: "failure"},
+			},
+			options: []SummaryOption{WithSeverity(SeverityWarning, "A")},
+			status:  metav1.ConditionFalse,
+			reason:  "Fail",
+			message: "B: failure",
+		},
+		{
+			name: "negative polarity",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionTrue, Reason: "Ok"},
+				{Type: "Paused", Status: metav1.ConditionTrue, Reason: "Paused", Message: "paused"},
+			},
+			options: []SummaryOption{WithNegativePolarityConditions("Paused")},
+			status:  metav1.ConditionFalse,
+			reason:  "Paused",
+			message: "Paused: paused",
+		},
+		{
+			name: "unknown",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionUnknown, Reason: "Unknown", Message: "unknown"},
+			},
+			status:  metav1.ConditionUnknown,
+			reason:  "Unknown",
+			message: "A: unknown",
+		},
+		{
+			name: "bad beats unknown",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionUnknown, Reason: "Unknown", Message: "unknown"},
+				{Type: "B", Status: metav1.ConditionFalse, Reason: "Fail", Message: "failure"},
+			},
+			status:  metav1.ConditionFalse,
+			reason:  "Fail",
+			message: "B: failure",
+		},
+		{
+			name: "bad beats more severe unknown",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionUnknown, Reason: "Unknown", Message: "unknown"},
+				{Type: "B", Status: metav1.ConditionFalse, Reason: "Warn", Message: "warning"},
+			},
+			options: []SummaryOption{WithSeverity(SeverityWarning, "B")},
+			status:  metav1.ConditionFalse,
+			reason:  "Warn",
+			message: "B: warning",
+		},
+		{
+			name: "restricted",
+			conditions: Conditions{
+				{Type: "A", Status: metav1.ConditionTrue, Reason: "Ok"},
+				{Type: "B", Status: metav1.ConditionFalse, Reason: "Fail", Message: "failure"},
+			},
+			options: []SummaryOption{WithConditions("A")},
+			status:  metav1.ConditionTrue,
+			reason:  "Ready",
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+
+		t.Run(tt.name, func(t *testing.T) {
+			g := getter{conditions: tt.conditions}
+			g.Generation = 3
+
+			c := Summary(&g, "Ready", tt.options...)
+
+			assert.Equal(t, "Ready", c.Type)
+			assert.Equal(t, tt.status, c.Status)
+			assert.Equal(t, tt.reason, c.Reason)
+			assert.Equal(t, int64(3), c.ObservedGeneration)
+
+			if tt.message != "" {
+				assert.Equal(t, tt.message, c.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+	"github.com/sco1237896/sco-operator/pkg/logger"
+)
+
+const (
+	APIVersion = "config.sco1237896.github.com/v1alpha1"
+	Kind       = "OperatorConfiguration"
+
+	// FeatureStorageMigration migrates the stored Workspaces to the storage version at startup.
+	FeatureStorageMigration = "StorageMigration"
+)
+
+// Features holds the known feature gates and whether they are enabled by default.
+var Features = map[string]bool{
+	FeatureStorageMigration: true,
+}
+
+// Configuration is the content of the configuration file of the operator. The fields
+// that are not set keep their default, the flags of the operator take precedence.
+type Configuration struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// WorkspaceDefaults holds the operator level defaults of the Workspaces, they
+	// are reloaded at runtime.
+	WorkspaceDefaults defaults.Workspace `json:"workspaceDefaults,omitempty"`
+	// Controller configures the concurrency and the intervals of the controller,
+	// changes require a restart but for the capabilities refresh interval.
+	Controller Controller `json:"controller,omitempty"`
+	// FeatureGates enables or disables the features by name, changes require a restart.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// Logging configures the loggers, the level and the overrides are reloaded at runtime.
+	Logging Logging `json:"logging,omitempty"`
+}
+
+type Controller struct {
+	// MaxConcurrentReconciles is the number of Workspaces reconciled at once.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+	// SyncPeriod is how often the cached objects are reconciled again.
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+	// CapabilitiesRefreshInterval is how often the capabilities of the cluster are discovered again.
+	CapabilitiesRefreshInterval *metav1.Duration `json:"capabilitiesRefreshInterval,omitempty"`
+	// EventDeduplicationInterval is how long identical events are suppressed.
+	EventDeduplicationInterval *metav1.Duration `json:"eventDeduplicationInterval,omitempty"`
+}
+
+type Logging struct {
+	// Level is either one of debug, info, error or an integer value > 0 for
+	// custom debug levels of increasing verbosity.
+	Level string `json:"level,omitempty"`
+	// Overrides configures the level and the format of named loggers as
+	// [level][:format], see the log-override flag.
+	Overrides map[string]string `json:"overrides,omitempty"`
+}
+
+// Load reads and validates the configuration file at the given path.
+func Load(path string) (Configuration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	return Parse(data)
+}
+
+// Parse decodes and validates the given configuration, unknown fields are rejected.
+func Parse(data []byte) (Configuration, error) {
+	c := Configuration{}
+
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return Configuration{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return Configuration{}, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return c, nil
+}
+
+// Validate returns an error listing all the invalid fields of the configuration.
+func (c Configuration) Validate() error {
+	allErrs := field.ErrorList{}
+
+	if c.APIVersion != APIVersion {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("apiVersion"), c.APIVersion, []string{APIVersion}))
+	}
+	if c.Kind != Kind {
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("kind"), c.Kind, []string{Kind}))
+	}
+
+	if err := c.WorkspaceDefaults.Validate(); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("workspaceDefaults"), field.OmitValueType{}, err.Error()))
+	}
+
+	controllerPath := field.NewPath("controller")
+
+	if c.Controller.MaxConcurrentReconciles < 0 {
+		allErrs = append(allErrs, field.Invalid(controllerPath.Child("maxConcurrentReconciles"), c.Controller.MaxConcurrentReconciles, "must be greater than or equal to 0"))
+	}
+
+	for _, d := range []struct {
+		name  string
+		value *metav1.Duration
+	}{
+		{name: "syncPeriod", value: c.Controller.SyncPeriod},
+		{name: "capabilitiesRefreshInterval", value: c.Controller.CapabilitiesRefreshInterval},
+		{name: "eventDeduplicationInterval", value: c.Controller.EventDeduplicationInterval},
+	} {
+		if d.value != nil && d.value.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(controllerPath.Child(d.name), d.value.Duration.String(), "must be greater than 0"))
+		}
+	}
+
+	for _, name := range sortedKeys(c.FeatureGates) {
+		if _, ok := Features[name]; !ok {
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("featureGates").Key(name), name, sortedKeys(Features)))
+		}
+	}
+
+	loggingPath := field.NewPath("logging")
+
+	if c.Logging.Level != "" {
+		if err := logger.ValidateLevel(c.Logging.Level); err != nil {
+			allErrs = append(allErrs, field.Invalid(loggingPath.Child("level"), c.Logging.Level, err.Error()))
+		}
+	}
+
+	for _, name := range sortedKeys(c.Logging.Overrides) {
+		o := logger.Overrides{}
+		if err := o.Set(name + "=" + c.Logging.Overrides[name]); err != nil {
+			allErrs = append(allErrs, field.Invalid(loggingPath.Child("overrides").Key(name), c.Logging.Overrides[name], err.Error()))
+		}
+	}
+
+	return allErrs.ToAggregate()
+}
+
+// FeatureEnabled returns true if the feature with the given name is enabled, either
+// through the feature gates or by default.
+func (c Configuration) FeatureEnabled(name string) bool {
+	if enabled, ok := c.FeatureGates[name]; ok {
+		return enabled
+	}
+
+	return Features[name]
+}
+
+// LogOverrides returns the logger overrides of the configuration, which must be valid.
+func (c Configuration) LogOverrides() logger.Overrides {
+	answer := logger.Overrides{}
+
+	for name, value := range c.Logging.Overrides {
+		// already validated
+		_ = answer.Set(name + "=" + value)
+	}
+
+	return answer
+}
+
+// RestartRequired returns the paths of the fields that differ between the given
+// configurations and that are only read at startup.
+func RestartRequired(old Configuration, updated Configuration) []string {
+	answer := make([]string, 0)
+
+	controller := field.NewPath("controller")
+
+	if old.Controller.MaxConcurrentReconciles != updated.Controller.MaxConcurrentReconciles {
+		answer = append(answer, controller.Child("maxConcurrentReconciles").String())
+	}
+	if Duration(old.Controller.SyncPeriod) != Duration(updated.Controller.SyncPeriod) {
+		answer = append(answer, controller.Child("syncPeriod").String())
+	}
+	if Duration(old.Controller.EventDeduplicationInterval) != Duration(updated.Controller.EventDeduplicationInterval) {
+		answer = append(answer, controller.Child("eventDeduplicationInterval").String())
+	}
+
+	for _, name := range sortedKeys(Features) {
+		if old.FeatureEnabled(name) != updated.FeatureEnabled(name) {
+			answer = append(answer, field.NewPath("featureGates").Key(name).String())
+		}
+	}
+
+	return answer
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Duration returns the duration held by the given value, or zero if not set.
+func Duration(d *metav1.Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+
+	return d.Duration
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/logger"
+)
+
+const sample = `
+apiVersion: config.sco1237896.github.com/v1alpha1
+kind: OperatorConfiguration
+workspaceDefaults:
+  registry:
+    address: registry.local:5000
+    insecure: true
+  buildStrategy: pod
+  exposeMode: Ingress
+controller:
+  maxConcurrentReconciles: 4
+  syncPeriod: 1h
+featureGates:
+  StorageMigration: false
+logging:
+  level: debug
+  overrides:
+    controller: error:json
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(sample))
+	require.NoError(t, err)
+
+	assert.Equal(t, "registry.local:5000", c.WorkspaceDefaults.Registry.Address)
+	assert.True(t, c.WorkspaceDefaults.Registry.Insecure)
+	assert.Equal(t, v1beta1.BuildStrategyPod, c.WorkspaceDefaults.BuildStrategy)
+	assert.Equal(t, v1beta1.ExposeModeIngress, c.WorkspaceDefaults.ExposeMode)
+	assert.Equal(t, 4, c.Controller.MaxConcurrentReconciles)
+	assert.Equal(t, time.Hour, Duration(c.Controller.SyncPeriod))
+	assert.Zero(t, Duration(c.Controller.CapabilitiesRefreshInterval))
+	assert.False(t, c.FeatureEnabled(FeatureStorageMigration))
+	assert.Equal(t, "debug", c.Logging.Level)
+	assert.Equal(t, logger.Overrides{"controller": {Level: "error", Format: logger.FormatJSON}}, c.LogOverrides())
+
+	// the unknown fields are rejected
+	_, err = Parse([]byte(sample + "unknown: true\n"))
+	assert.ErrorContains(t, err, "unknown")
+
+	// the version is required
+	_, err = Parse([]byte("kind: OperatorConfiguration\n"))
+	assert.ErrorContains(t, err, "apiVersion")
+}
+
+func TestValidate(t *testing.T) {
+	c := Configuration{APIVersion: APIVersion, Kind: Kind}
+	require.NoError(t, c.Validate())
+	assert.True(t, c.FeatureEnabled(FeatureStorageMigration))
+
+	c.WorkspaceDefaults.BuildStrategy = "kaniko"
+	c.Controller.MaxConcurrentReconciles = -1
+	c.FeatureGates = map[string]bool{"Unknown": true}
+	c.Logging.Level = "verbose"
+	c.Logging.Overrides = map[string]string{"controller": "debug:xml"}
+
+	err := c.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "workspaceDefaults")
+	assert.ErrorContains(t, err, "controller.maxConcurrentReconciles")
+	assert.ErrorContains(t, err, "featureGates[Unknown]")
+	assert.ErrorContains(t, err, "logging.level")
+	assert.ErrorContains(t, err, "logging.overrides[controller]")
+}
+
+func TestRestartRequired(t *testing.T) {
+	old, err := Parse([]byte(sample))
+	require.NoError(t, err)
+
+	updated := old
+	updated.WorkspaceDefaults.RuntimeVersion = "3.2.0"
+	updated.Logging.Level = "info"
+	assert.Empty(t, RestartRequired(old, updated))
+
+	// applied at runtime
+	updated.Controller.CapabilitiesRefreshInterval = &metav1.Duration{Duration: time.Minute}
+	updated.Logging.Overrides = nil
+	assert.Empty(t, RestartRequired(old, updated))
+
+	updated.Controller.MaxConcurrentReconciles = 1
+	updated.Controller.SyncPeriod = &metav1.Duration{Duration: 2 * time.Hour}
+	updated.FeatureGates = nil
+	assert.Equal(t, []string{
+		"controller.maxConcurrentReconciles",
+		"controller.syncPeriod",
+		"featureGates[StorageMigration]",
+	}, RestartRequired(old, updated))
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(sample), 0o600))
+
+	s := NewStore(path)
+
+	c, err := s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, c, s.Get())
+
+	notified := make([]Configuration, 0)
+	s.OnChange(func(c Configuration) {
+		notified = append(notified, c)
+	})
+
+	// nothing changed
+	changed, err := s.Reload(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// the content changed but not the configuration
+	require.NoError(t, os.WriteFile(path, []byte(sample+"  "), 0o600))
+
+	changed, err = s.Reload(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// invalid configurations are ignored
+	require.NoError(t, os.WriteFile(path, []byte(sample+"  level: info\n"), 0o600))
+
+	_, err = s.Reload(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, c, s.Get())
+
+	// the overrides are removed
+	require.NoError(t, os.WriteFile(path, []byte(sample[:len(sample)-len("    controller: error:json\n")]), 0o600))
+
+	changed, err = s.Reload(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	require.Len(t, notified, 1)
+	assert.Nil(t, notified[0].Logging.Overrides)
+	assert.Equal(t, notified[0], s.Get())
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// DefaultReloadInterval is how often the configuration file is checked for changes.
+// The file is polled rather than watched as the files mounted from a ConfigMap are
+// replaced through symbolic links.
+const DefaultReloadInterval = 10 * time.Second
+
+type Option func(*Store)
+
+// WithReloadInterval sets how often the configuration file is checked for changes.
+func WithReloadInterval(interval time.Duration) Option {
+	return func(s *Store) {
+		s.interval = interval
+	}
+}
+
+// WithLogger sets the logger used to report configuration changes.
+func WithLogger(l logr.Logger) Option {
+	return func(s *Store) {
+		s.l = l
+	}
+}
+
+// Store loads the configuration file and reloads it when it changes once started.
+// An invalid configuration is reported and ignored, the last valid one is kept.
+// It implements manager.Runnable.
+type Store struct {
+	path     string
+	interval time.Duration
+	l        logr.Logger
+
+	// reload serializes the reloads, so that the listeners are notified of the
+	// configurations in order
+	reload sync.Mutex
+
+	lock      sync.RWMutex
+	data      []byte
+	current   Configuration
+	listeners []func(Configuration)
+}
+
+func NewStore(path string, opts ...Option) *Store {
+	s := Store{
+		path:     path,
+		interval: DefaultReloadInterval,
+		l:        logr.Discard(),
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return &s
+}
+
+// Get returns the last valid configuration.
+func (s *Store) Get() Configuration {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.current
+}
+
+// OnChange registers a function invoked with the new configuration every time a
+// reload detects a change.
+func (s *Store) OnChange(listener func(Configuration)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.listeners = append(s.listeners, listener)
+}
+
+// Load reads the configuration file, it is meant to be used at startup as the
+// listeners are not notified.
+func (s *Store) Load() (Configuration, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	c, err := Parse(data)
+	if err != nil {
+		return Configuration{}, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.data = data
+	s.current = c
+
+	return c, nil
+}
+
+// Reload reads the configuration file again and returns true if the configuration
+// has changed since the last load. Concurrent reloads are serialized, hence listeners
+// must not reload the store.
+func (s *Store) Reload(_ context.Context) (bool, error) {
+	s.reload.Lock()
+	defer s.reload.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return false, err
+	}
+
+	s.lock.Lock()
+
+	if bytes.Equal(s.data, data) {
+		s.lock.Unlock()
+		return false, nil
+	}
+
+	// remember the content even if invalid, so that it is reported once
+	s.data = data
+
+	c, err := Parse(data)
+	if err != nil {
+		s.lock.Unlock()
+		return false, err
+	}
+
+	if reflect.DeepEqual(s.current, c) {
+		s.lock.Unlock()
+		return false, nil
+	}
+
+	s.l.Info("Configuration reloaded", "path", s.path)
+
+	if fields := RestartRequired(s.current, c); len(fields) > 0 {
+		s.l.Info("Configuration changes require a restart to be applied", "fields", fields)
+	}
+
+	s.current = c
+	listeners := s.listeners
+
+	s.lock.Unlock()
+
+	// listeners are notified without holding the lock so they can use the store
+	for _, listener := range listeners {
+		listener(c)
+	}
+
+	return true, nil
+}
+
+// Start reloads the configuration until the context is done.
+func (s *Store) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := s.Reload(ctx); err != nil {
+				s.l.Error(err, "unable to reload the configuration", "path", s.path)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns false as the configuration is required by all the replicas.
+func (s *Store) NeedLeaderElection() bool {
+	return false
+}
@@ -3,15 +3,17 @@ package client
 import (
 	camel "github.com/apache/camel-k/v2/pkg/client/camel/clientset/versioned"
 	route "github.com/openshift/client-go/route/clientset/versioned"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/scale"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sco1237896/sco-operator/pkg/metrics"
 )
 
 var scaleConverter = scale.NewScaleConverter()
@@ -23,6 +25,7 @@ type Client struct {
 	Camel camel.Interface
 
 	Discovery discovery.DiscoveryInterface
+	Dynamic   dynamic.Interface
 	Route     route.Interface
 
 	scheme *runtime.Scheme
@@ -30,7 +33,11 @@ type Client struct {
 	rest   rest.Interface
 }
 
-func NewClient(cfg *rest.Config, scheme *runtime.Scheme, cc ctrl.Client) (*Client, error) {
+func NewClient(config *rest.Config, scheme *runtime.Scheme, cc ctrl.Client) (*Client, error) {
+	// the typed clients bypass the instrumentation of the controller-runtime
+	// client, so record their requests at the transport level
+	cfg := rest.CopyConfig(config)
+	cfg.Wrap(metrics.NewRoundTripper())
 
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
 	if err != nil {
@@ -49,30 +56,32 @@ func NewClient(cfg *rest.Config, scheme *runtime.Scheme, cc ctrl.Client) (*Clien
 		return nil, err
 	}
 
+	// used for the resources whose API version depends on the Camel K release
+	// installed in the cluster, see pkg/camel
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// creating the client does not require routes to be served, use the
+	// capabilities to find out if they can be used
+	routeClient, err := route.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	c := Client{
 		Client:    cc,
 		Interface: kubeClient,
 		Camel:     camelClient,
 		Discovery: discoveryClient,
+		Dynamic:   dynamicClient,
+		Route:     routeClient,
 		scheme:    scheme,
 		config:    cfg,
 		rest:      restClient,
 	}
 
-	io, err := IsOpenShift(discoveryClient)
-	if err != nil {
-		return nil, err
-	}
-
-	if io {
-		routeClient, err := route.NewForConfig(cfg)
-		if err != nil {
-			return nil, err
-		}
-
-		c.Route = routeClient
-	}
-
 	return &c, nil
 }
 
@@ -87,23 +96,3 @@ func NewRESTClientForConfig(config *rest.Config) (*rest.RESTClient, error) {
 
 	return rest.RESTClientFor(cfg)
 }
-
-// IsOpenShift returns true if we are connected to a OpenShift cluster.
-func (c *Client) IsOpenShift() (bool, error) {
-	if c.Discovery == nil {
-		return false, nil
-	}
-
-	return IsOpenShift(c.Discovery)
-}
-
-func IsOpenShift(d discovery.DiscoveryInterface) (bool, error) {
-	_, err := d.ServerResourcesForGroupVersion("route.openshift.io/v1")
-	if err != nil && k8serrors.IsNotFound(err) {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-
-	return true, nil
-}
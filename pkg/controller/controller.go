@@ -1,12 +1,14 @@
 package controller
 
 import (
+	"context"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"time"
 
 	"github.com/sco1237896/sco-operator/pkg/logger"
+	"github.com/sco1237896/sco-operator/pkg/tracing"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
@@ -18,9 +20,10 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var (
@@ -33,12 +36,39 @@ func init() {
 }
 
 func Start(options Options, setup func(manager.Manager, Options) error) error {
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&logger.Options)))
+	ctrl.SetLogger(logger.New(&logger.Options))
 
 	ctx := ctrl.SetupSignalHandler()
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	shutdownTracing, err := tracing.Setup(options.Tracing)
+	if err != nil {
+		Log.Error(err, "unable to set up tracing")
+		return err
+	}
+
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			Log.Error(err, "unable to shut down tracing")
+		}
+	}()
+
+	cfg := ctrl.GetConfigOrDie()
+	cfg.Wrap(tracing.NewRoundTripper())
+
+	// the config is shared by the manager and by all the clients created
+	// out of it, hence wrapping its transport is enough to log every call
+	if options.EnableAPIClientLogging {
+		cfg.Wrap(logger.NewLoggingRoundTripper(ctrl.Log.WithName("api-client")))
+	}
+
+	cacheOpts := cache.Options{}
+	if options.SyncPeriod > 0 {
+		cacheOpts.SyncPeriod = &options.SyncPeriod
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:                        Scheme,
+		Cache:                         cacheOpts,
 		HealthProbeBindAddress:        options.ProbeAddr,
 		LeaderElection:                options.EnableLeaderElection,
 		LeaderElectionID:              options.LeaderElectionID,
@@ -48,6 +78,10 @@ func Start(options Options, setup func(manager.Manager, Options) error) error {
 		Metrics: metricsserver.Options{
 			BindAddress: options.MetricsAddr,
 		},
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Port:    options.WebhookPort,
+			CertDir: options.WebhookCertDir,
+		}),
 	})
 	if err != nil {
 		Log.Error(err, "unable to create manager")
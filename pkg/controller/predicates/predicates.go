@@ -54,21 +54,12 @@ func (in AnnotationChanged) Update(e event.UpdateEvent) bool {
 		Log.Error(nil, "Update event has no old object to update", "event", e)
 		return false
 	}
-	if e.ObjectOld.GetAnnotations() == nil {
-		Log.Error(nil, "Update event has no old object annotations to update", "event", e)
-		return false
-	}
 
 	if e.ObjectNew == nil {
 		Log.Error(nil, "Update event has no new object for update", "event", e)
 		return false
 	}
 
-	if e.ObjectNew.GetAnnotations() == nil {
-		Log.Error(nil, "Update event has no new object annotations for update", "event", e)
-		return false
-	}
-
 	oldAnnotations := e.ObjectOld.GetAnnotations()
 	newAnnotations := e.ObjectNew.GetAnnotations()
 
@@ -0,0 +1,35 @@
+package predicates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestAnnotationChanged(t *testing.T) {
+	p := AnnotationChanged{Name: "foo"}
+
+	withAnnotations := func(annotations map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	assert.True(t, p.Update(event.UpdateEvent{
+		ObjectOld: withAnnotations(nil),
+		ObjectNew: withAnnotations(map[string]string{"foo": "true"}),
+	}))
+	assert.True(t, p.Update(event.UpdateEvent{
+		ObjectOld: withAnnotations(map[string]string{"foo": "true"}),
+		ObjectNew: withAnnotations(nil),
+	}))
+	assert.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: withAnnotations(map[string]string{"foo": "true"}),
+		ObjectNew: withAnnotations(map[string]string{"foo": "true", "bar": "baz"}),
+	}))
+	assert.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: withAnnotations(nil),
+		ObjectNew: withAnnotations(nil),
+	}))
+}
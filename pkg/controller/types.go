@@ -2,10 +2,19 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	"github.com/go-logr/logr"
+
+	"github.com/sco1237896/sco-operator/pkg/camel"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
 	"github.com/sco1237896/sco-operator/pkg/controller/client"
+	"github.com/sco1237896/sco-operator/pkg/defaults"
+	"github.com/sco1237896/sco-operator/pkg/tracing"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type Options struct {
@@ -16,14 +25,30 @@ type Options struct {
 	LeaderElectionNamespace       string
 	EnableLeaderElection          bool
 	ReleaseLeaderElectionOnCancel bool
+	EnableAPIClientLogging        bool
+	EnableWebhooks                bool
+	WebhookPort                   int
+	WebhookCertDir                string
+	WebhookTrustedUser            string
+	EnableStorageMigration        bool
+	MaxConcurrentReconciles       int
+	SyncPeriod                    time.Duration
+	CapabilitiesRefreshInterval   time.Duration
+	EventDeduplicationInterval    time.Duration
+	ConfigFile                    string
+	Tracing                       tracing.Options
+	Defaults                      defaults.Workspace
 }
 
-type ClusterType string
-
+// Keys used to correlate the log entries of a reconciliation.
 const (
-	ClusterTypeVanilla   ClusterType = "Vanilla"
-	ClusterTypeOpenShift ClusterType = "OpenShift"
+	LogKeyReconcileID = "reconcileID"
+	LogKeyResource    = "resource"
+	LogKeyGeneration  = "generation"
+	LogKeyAction      = "action"
+)
 
+const (
 	KubernetesLabelAppName      = "app.kubernetes.io/name"
 	KubernetesLabelAppInstance  = "app.kubernetes.io/instance"
 	KubernetesLabelAppComponent = "app.kubernetes.io/component"
@@ -35,12 +60,45 @@ type ReconciliationRequest[T any] struct {
 	*client.Client
 	types.NamespacedName
 
-	ClusterType ClusterType
-	Resource    *T
+	Capabilities capabilities.Capabilities
+	Resource     *T
+	Recorder     record.EventRecorder
+
+	// CamelAPI holds the Camel K resources to use according to the
+	// version of Camel K installed in the cluster.
+	CamelAPI camel.API
+
+	// Log is scoped to the current reconciliation and carries its
+	// correlation ID, see LogKeyReconcileID.
+	Log logr.Logger
 }
 
 type Action[T any] interface {
+	Name() string
 	Configure(context.Context, *client.Client, *builder.Builder) (*builder.Builder, error)
 	Apply(context.Context, *ReconciliationRequest[T]) error
 	Cleanup(context.Context, *ReconciliationRequest[T]) error
 }
+
+// CapabilitiesAware can be implemented by actions that can only run when the
+// cluster provides the given capabilities. Such actions are skipped otherwise.
+type CapabilitiesAware interface {
+	RequiredCapabilities() []capabilities.Capability
+}
+
+// SkipAware can be implemented by CapabilitiesAware actions reporting their outcome
+// through the status of the resource. Skipped is invoked in place of Apply when the
+// required capabilities are missing, so that the action can reset a status which
+// would otherwise be stale, e.g. after Camel K has been uninstalled.
+type SkipAware[T any] interface {
+	Skipped(*ReconciliationRequest[T], []capabilities.Capability)
+}
+
+// OwnerAware can be implemented by actions owning resources that may only be
+// served once the operator is running, e.g. because they belong to an optional
+// capability. Such resources are watched as soon as they are served rather than
+// through Configure.
+type OwnerAware interface {
+	// OwnedTypes returns the owned types served according to the given capabilities.
+	OwnedTypes(capabilities.Capabilities) []ctrlclient.Object
+}
@@ -7,5 +7,6 @@ const (
 	RetryInterval    = 10 * time.Second
 	ConflictInterval = 1 * time.Second
 
-	FinalizerName = "sco1237896.github.com/finalizer"
+	FinalizerName    = "sco1237896.github.com/finalizer"
+	PausedAnnotation = "sco1237896.github.com/paused"
 )
@@ -0,0 +1,183 @@
+package defaults
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"text/template"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+)
+
+// Workspace holds the operator level defaults of the Workspaces. They are applied
+// at admission time by the defaulting webhook and in memory by the reconciler, so
+// that the Workspaces created before the webhook existed behave identically.
+type Workspace struct {
+	// Registry is the registry the images are published to, unless set by the Workspace.
+	Registry *v1beta1.RegistrySpec `json:"registry,omitempty"`
+	// BuildStrategy is the strategy used to build the integrations, unless set by the Workspace.
+	BuildStrategy v1beta1.BuildStrategy `json:"buildStrategy,omitempty"`
+	// RuntimeVersion is the Camel K runtime version to use, unless set by the Workspace.
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+	// ExposeMode is the preferred exposure mode, it is only used when supported by the cluster.
+	ExposeMode v1beta1.ExposeMode `json:"exposeMode,omitempty"`
+}
+
+// Provider holds the Workspace defaults currently in use, they may be replaced at
+// runtime, e.g. when the configuration of the operator is reloaded.
+type Provider struct {
+	current atomic.Pointer[Workspace]
+}
+
+func NewProvider(d Workspace) *Provider {
+	p := Provider{}
+	p.Set(d)
+
+	return &p
+}
+
+// Get returns a copy of the current defaults.
+func (p *Provider) Get() Workspace {
+	answer := *p.current.Load()
+	answer.Registry = answer.Registry.DeepCopy()
+
+	return answer
+}
+
+// Set replaces the current defaults with a copy of the given ones, so that they
+// do not share any state with the caller, e.g. the options bound to the flags.
+func (p *Provider) Set(d Workspace) {
+	d.Registry = d.Registry.DeepCopy()
+	p.current.Store(&d)
+}
+
+// Validate returns an error if the defaults hold values the Workspace API does not accept.
+func (d Workspace) Validate() error {
+	switch d.BuildStrategy {
+	case "", v1beta1.BuildStrategyRoutine, v1beta1.BuildStrategyPod:
+	default:
+		return fmt.Errorf("unsupported build strategy %q", d.BuildStrategy)
+	}
+
+	if d.ExposeMode != "" && exposeModeCapabilities[d.ExposeMode] == "" {
+		return fmt.Errorf("unsupported expose mode %q", d.ExposeMode)
+	}
+
+	return nil
+}
+
+// Apply sets the fields of the given Workspace that are left empty to their default.
+func (d Workspace) Apply(ws *v1beta1.Workspace, caps capabilities.Capabilities) {
+	if d.Registry != nil || d.BuildStrategy != "" || d.RuntimeVersion != "" {
+		if ws.Spec.Platform == nil {
+			ws.Spec.Platform = &v1beta1.PlatformSpec{}
+		}
+		if ws.Spec.Platform.Build == nil {
+			ws.Spec.Platform.Build = &v1beta1.BuildSpec{}
+		}
+
+		build := ws.Spec.Platform.Build
+
+		if build.Registry == nil && d.Registry != nil {
+			build.Registry = d.Registry.DeepCopy()
+		}
+		if build.Strategy == "" {
+			build.Strategy = d.BuildStrategy
+		}
+		if build.RuntimeVersion == "" {
+			build.RuntimeVersion = d.RuntimeVersion
+		}
+	}
+
+	if ws.Spec.Expose != nil && ws.Spec.Expose.Mode == "" {
+		ws.Spec.Expose.Mode = ExposeMode(ws.Spec.Expose, d.ExposeMode, caps)
+	}
+}
+
+// exposeModeCapabilities maps each exposure mode to the capability it requires.
+var exposeModeCapabilities = map[v1beta1.ExposeMode]capabilities.Capability{
+	v1beta1.ExposeModeRoute:   capabilities.Routes,
+	v1beta1.ExposeModeIngress: capabilities.Ingress,
+	v1beta1.ExposeModeGateway: capabilities.GatewayAPI,
+}
+
+// ExposeModeCapability returns the capability required by the given exposure mode.
+func ExposeModeCapability(mode v1beta1.ExposeMode) capabilities.Capability {
+	return exposeModeCapabilities[mode]
+}
+
+// ExposeModes returns the exposure modes supported by the cluster, by order of preference.
+func ExposeModes(caps capabilities.Capabilities) []v1beta1.ExposeMode {
+	answer := make([]v1beta1.ExposeMode, 0, len(exposeModeCapabilities))
+
+	for _, mode := range []v1beta1.ExposeMode{v1beta1.ExposeModeRoute, v1beta1.ExposeModeIngress, v1beta1.ExposeModeGateway} {
+		if caps.Has(exposeModeCapabilities[mode]) {
+			answer = append(answer, mode)
+		}
+	}
+
+	return answer
+}
+
+// ExposeMode selects the exposure mode of a spec that does not set one among the
+// modes supported by the cluster: the mode implied by the mode specific fields
+// first, then the preferred one, then the first supported one. An empty mode is
+// returned if none of the modes is supported.
+func ExposeMode(spec *v1beta1.ExposeSpec, preferred v1beta1.ExposeMode, caps capabilities.Capabilities) v1beta1.ExposeMode {
+	modes := ExposeModes(caps)
+
+	candidates := make([]v1beta1.ExposeMode, 0, 3)
+	if spec.Gateway != nil {
+		candidates = append(candidates, v1beta1.ExposeModeGateway)
+	}
+	if spec.IngressClassName != "" {
+		candidates = append(candidates, v1beta1.ExposeModeIngress)
+	}
+	if preferred != "" {
+		candidates = append(candidates, preferred)
+	}
+
+	for _, mode := range candidates {
+		if slices.Contains(modes, mode) {
+			return mode
+		}
+	}
+
+	if len(modes) == 0 {
+		return ""
+	}
+
+	return modes[0]
+}
+
+// RenderHost renders the given host template with the name and the namespace of
+// the Workspace. It is shared by the controller and the webhook so that the
+// templates accepted at admission time are rendered the same way.
+func RenderHost(host string, ws *v1beta1.Workspace) (string, error) {
+	if host == "" {
+		return "", nil
+	}
+
+	t, err := template.New("host").Option("missingkey=error").Parse(host)
+	if err != nil {
+		return "", fmt.Errorf("invalid host template: %w", err)
+	}
+
+	var b bytes.Buffer
+
+	err = t.Execute(&b, struct {
+		Name      string
+		Namespace string
+	}{
+		Name:      ws.Name,
+		Namespace: ws.Namespace,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("invalid host template: %w", err)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,152 @@
+package defaults
+
+import (
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/sco1237896/sco-operator/api/sco/v1beta1"
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
+)
+
+var (
+	routes     = routev1.GroupVersion.WithResource("routes")
+	ingresses  = networkingv1.SchemeGroupVersion.WithResource("ingresses")
+	httpRoutes = schema.GroupVersionResource{Group: capabilities.GatewayAPIGroup, Version: "v1", Resource: "httproutes"}
+)
+
+func TestWorkspaceApply(t *testing.T) {
+	d := Workspace{
+		Registry:       &v1beta1.RegistrySpec{Address: "registry.local:5000", Insecure: true},
+		BuildStrategy:  v1beta1.BuildStrategyRoutine,
+		RuntimeVersion: "3.2.0",
+	}
+
+	ws := v1beta1.Workspace{}
+	d.Apply(&ws, capabilities.New())
+
+	require.NotNil(t, ws.Spec.Platform)
+	require.NotNil(t, ws.Spec.Platform.Build)
+	assert.Equal(t, "registry.local:5000", ws.Spec.Platform.Build.Registry.Address)
+	assert.Equal(t, v1beta1.BuildStrategyRoutine, ws.Spec.Platform.Build.Strategy)
+	assert.Equal(t, "3.2.0", ws.Spec.Platform.Build.RuntimeVersion)
+	assert.Nil(t, ws.Spec.Expose)
+
+	// the defaults are copied
+	ws.Spec.Platform.Build.Registry.Address = "other"
+	assert.Equal(t, "registry.local:5000", d.Registry.Address)
+
+	// the values set by the Workspace are kept
+	ws = v1beta1.Workspace{
+		Spec: v1beta1.WorkspaceSpec{
+			Platform: &v1beta1.PlatformSpec{
+				Build: &v1beta1.BuildSpec{
+					Strategy: v1beta1.BuildStrategyPod,
+					Registry: &v1beta1.RegistrySpec{Address: "quay.io"},
+				},
+			},
+		},
+	}
+	d.Apply(&ws, capabilities.New())
+
+	assert.Equal(t, "quay.io", ws.Spec.Platform.Build.Registry.Address)
+	assert.Equal(t, v1beta1.BuildStrategyPod, ws.Spec.Platform.Build.Strategy)
+	assert.Equal(t, "3.2.0", ws.Spec.Platform.Build.RuntimeVersion)
+
+	// nothing to default
+	ws = v1beta1.Workspace{}
+	Workspace{}.Apply(&ws, capabilities.New())
+	assert.Nil(t, ws.Spec.Platform)
+}
+
+func TestWorkspaceApplyExposeMode(t *testing.T) {
+	ws := v1beta1.Workspace{
+		Spec: v1beta1.WorkspaceSpec{
+			Expose: &v1beta1.ExposeSpec{Service: "svc"},
+		},
+	}
+
+	Workspace{}.Apply(&ws, capabilities.New())
+	assert.Empty(t, ws.Spec.Expose.Mode)
+
+	Workspace{ExposeMode: v1beta1.ExposeModeGateway}.Apply(&ws, capabilities.New(routes, httpRoutes))
+	assert.Equal(t, v1beta1.ExposeModeGateway, ws.Spec.Expose.Mode)
+
+	// the mode is never changed once set
+	Workspace{}.Apply(&ws, capabilities.New(routes))
+	assert.Equal(t, v1beta1.ExposeModeGateway, ws.Spec.Expose.Mode)
+}
+
+func TestExposeMode(t *testing.T) {
+	spec := v1beta1.ExposeSpec{}
+
+	assert.Empty(t, ExposeMode(&spec, "", capabilities.New()))
+	assert.Equal(t, v1beta1.ExposeModeRoute, ExposeMode(&spec, "", capabilities.New(routes, ingresses, httpRoutes)))
+	assert.Equal(t, v1beta1.ExposeModeGateway, ExposeMode(&spec, "", capabilities.New(httpRoutes)))
+
+	// the preferred mode is only used when supported
+	assert.Equal(t, v1beta1.ExposeModeIngress, ExposeMode(&spec, v1beta1.ExposeModeIngress, capabilities.New(routes, ingresses)))
+	assert.Equal(t, v1beta1.ExposeModeRoute, ExposeMode(&spec, v1beta1.ExposeModeGateway, capabilities.New(routes, ingresses)))
+
+	// the mode specific fields take precedence
+	spec.IngressClassName = "nginx"
+	assert.Equal(t, v1beta1.ExposeModeIngress, ExposeMode(&spec, v1beta1.ExposeModeRoute, capabilities.New(routes, ingresses)))
+
+	spec.Gateway = &v1beta1.GatewayReference{Name: "gw"}
+	assert.Equal(t, v1beta1.ExposeModeGateway, ExposeMode(&spec, v1beta1.ExposeModeRoute, capabilities.New(routes, ingresses, httpRoutes)))
+}
+
+func TestRenderHost(t *testing.T) {
+	ws := v1beta1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws",
+			Namespace: "ns",
+		},
+	}
+
+	host, err := RenderHost("", &ws)
+	require.NoError(t, err)
+	assert.Empty(t, host)
+
+	host, err = RenderHost("{{ .Name }}-{{ .Namespace }}.apps.example.com", &ws)
+	require.NoError(t, err)
+	assert.Equal(t, "ws-ns.apps.example.com", host)
+
+	_, err = RenderHost("{{ .Name ", &ws)
+	assert.ErrorContains(t, err, "invalid host template")
+
+	_, err = RenderHost("{{ .Cluster }}.example.com", &ws)
+	assert.ErrorContains(t, err, "invalid host template")
+}
+
+func TestWorkspaceValidate(t *testing.T) {
+	assert.NoError(t, Workspace{}.Validate())
+	assert.NoError(t, Workspace{BuildStrategy: v1beta1.BuildStrategyPod, ExposeMode: v1beta1.ExposeModeIngress}.Validate())
+	assert.ErrorContains(t, Workspace{BuildStrategy: "kaniko"}.Validate(), "unsupported build strategy")
+	assert.ErrorContains(t, Workspace{ExposeMode: "LoadBalancer"}.Validate(), "unsupported expose mode")
+}
+
+func TestProvider(t *testing.T) {
+	p := NewProvider(Workspace{RuntimeVersion: "3.2.0"})
+	assert.Equal(t, "3.2.0", p.Get().RuntimeVersion)
+
+	p.Set(Workspace{BuildStrategy: v1beta1.BuildStrategyPod})
+	assert.Equal(t, Workspace{BuildStrategy: v1beta1.BuildStrategyPod}, p.Get())
+}
+
+func TestProviderCopy(t *testing.T) {
+	registry := v1beta1.RegistrySpec{Address: "registry.example.com"}
+
+	p := NewProvider(Workspace{Registry: &registry})
+
+	// the defaults do not share the registry with the caller, nor with the readers
+	registry.Address = "changed.example.com"
+	p.Get().Registry.Address = "changed.example.com"
+
+	assert.Equal(t, "registry.example.com", p.Get().Registry.Address)
+}
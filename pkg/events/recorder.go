@@ -0,0 +1,169 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/utils/clock"
+)
+
+const (
+	DefaultDeduplicationInterval = 5 * time.Minute
+	DefaultQPS                   = 1
+	DefaultBurst                 = 10
+)
+
+// Option is some configuration that modifies the Recorder.
+type Option func(*Recorder)
+
+// WithDeduplicationInterval sets how long an event identical to one already
+// emitted for the same object is suppressed.
+func WithDeduplicationInterval(interval time.Duration) Option {
+	return func(r *Recorder) {
+		r.interval = interval
+	}
+}
+
+// WithoutDeduplication excludes the events with the given reasons from the
+// deduplication, e.g. transitions that may legitimately repeat, they are only rate
+// limited.
+func WithoutDeduplication(reasons ...string) Option {
+	return func(r *Recorder) {
+		for _, reason := range reasons {
+			r.always[reason] = true
+		}
+	}
+}
+
+// WithRateLimit sets the per-object rate limit of the emitted events.
+func WithRateLimit(qps float32, burst int) Option {
+	return func(r *Recorder) {
+		r.qps = qps
+		r.burst = burst
+	}
+}
+
+// WithClock sets the clock used by the Recorder.
+func WithClock(c flowcontrol.Clock) Option {
+	return func(r *Recorder) {
+		r.clock = c
+	}
+}
+
+// Recorder is a record.EventRecorder that drops events identical to one recently
+// emitted for the same object and rate limits events on a per-object basis, so
+// that a hot reconcile loop does not flood the API server.
+type Recorder struct {
+	delegate record.EventRecorder
+	interval time.Duration
+	qps      float32
+	burst    int
+	clock    flowcontrol.Clock
+	always   map[string]bool
+
+	lock     sync.Mutex
+	seen     map[string]time.Time
+	limiters map[types.UID]*limiter
+}
+
+type limiter struct {
+	flowcontrol.RateLimiter
+	last time.Time
+}
+
+var _ record.EventRecorder = &Recorder{}
+
+func NewRecorder(delegate record.EventRecorder, options ...Option) *Recorder {
+	r := Recorder{
+		delegate: delegate,
+		interval: DefaultDeduplicationInterval,
+		qps:      DefaultQPS,
+		burst:    DefaultBurst,
+		clock:    clock.RealClock{},
+		always:   make(map[string]bool),
+		seen:     make(map[string]time.Time),
+		limiters: make(map[types.UID]*limiter),
+	}
+
+	for _, option := range options {
+		option(&r)
+	}
+
+	return &r
+}
+
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.accept(object, eventtype, reason, message) {
+		r.delegate.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+
+	if r.accept(object, eventtype, reason, message) {
+		r.delegate.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+func (r *Recorder) accept(object runtime.Object, eventtype, reason, message string) bool {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		// let the delegate deal with objects it can't reference
+		return true
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.clock.Now()
+
+	r.expire(now)
+
+	key := string(accessor.GetUID()) + "/" + eventtype + "/" + reason + "/" + message
+	if _, ok := r.seen[key]; ok && !r.always[reason] {
+		return false
+	}
+
+	l, ok := r.limiters[accessor.GetUID()]
+	if !ok {
+		l = &limiter{RateLimiter: flowcontrol.NewTokenBucketRateLimiterWithClock(r.qps, r.burst, r.clock)}
+		r.limiters[accessor.GetUID()] = l
+	}
+
+	l.last = now
+
+	if !l.TryAccept() {
+		return false
+	}
+
+	if !r.always[reason] {
+		r.seen[key] = now
+	}
+
+	return true
+}
+
+// expire forgets about events and objects not seen within the deduplication interval.
+func (r *Recorder) expire(now time.Time) {
+	for k, t := range r.seen {
+		if now.Sub(t) >= r.interval {
+			delete(r.seen, k)
+		}
+	}
+	for k, l := range r.limiters {
+		if now.Sub(l.last) >= r.interval {
+			delete(r.limiters, k)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestRecorderDeduplication(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Now())
+	fake := record.NewFakeRecorder(10)
+	r := NewRecorder(fake, WithClock(clock), WithDeduplicationInterval(time.Minute))
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "1"}}
+
+	r.Eventf(cm, corev1.EventTypeNormal, "Applied", "applied %s", "foo")
+	r.Eventf(cm, corev1.EventTypeNormal, "Applied", "applied %s", "foo")
+	r.Eventf(cm, corev1.EventTypeNormal, "Applied", "applied %s", "bar")
+
+	assert.Len(t, fake.Events, 2)
+
+	clock.Step(time.Minute)
+
+	r.Eventf(cm, corev1.EventTypeNormal, "Applied", "applied %s", "foo")
+
+	assert.Len(t, fake.Events, 3)
+}
+
+func TestRecorderWithoutDeduplication(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Now())
+	fake := record.NewFakeRecorder(10)
+	r := NewRecorder(fake, WithClock(clock), WithoutDeduplication("PhaseChanged"), WithRateLimit(1, 3))
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "1"}}
+
+	r.Event(cm, corev1.EventTypeWarning, "PhaseChanged", "Phase changed from Ready to Degraded")
+	r.Event(cm, corev1.EventTypeNormal, "PhaseChanged", "Phase changed from Degraded to Ready")
+	r.Event(cm, corev1.EventTypeWarning, "PhaseChanged", "Phase changed from Ready to Degraded")
+
+	assert.Len(t, fake.Events, 3)
+
+	// still rate limited
+	r.Event(cm, corev1.EventTypeNormal, "PhaseChanged", "Phase changed from Degraded to Ready")
+
+	assert.Len(t, fake.Events, 3)
+}
+
+func TestRecorderRateLimit(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Now())
+	fake := record.NewFakeRecorder(10)
+	r := NewRecorder(fake, WithClock(clock), WithRateLimit(1, 2))
+
+	cm1 := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "1"}}
+	cm2 := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: "2"}}
+
+	r.Event(cm1, corev1.EventTypeNormal, "A", "a")
+	r.Event(cm1, corev1.EventTypeNormal, "B", "b")
+	r.Event(cm1, corev1.EventTypeNormal, "C", "c")
+
+	// the limit is per object
+	r.Event(cm2, corev1.EventTypeNormal, "A", "a")
+
+	assert.Len(t, fake.Events, 3)
+
+	clock.Step(time.Second)
+
+	r.Event(cm1, corev1.EventTypeNormal, "C", "c")
+
+	assert.Len(t, fake.Events, 4)
+}
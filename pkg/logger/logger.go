@@ -1,9 +1,355 @@
 package logger
 
-import "sigs.k8s.io/controller-runtime/pkg/log/zap"
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
 
 var (
-	Options = zap.Options{
-		//Development: true,
+	Options = LogOptions{
+		Options: ctrlzap.Options{
+			//Development: true,
+		},
 	}
 )
+
+// LogOptions extends the zap options with per logger overrides.
+type LogOptions struct {
+	ctrlzap.Options
+
+	// Overrides configures the level and the format of the loggers whose
+	// name matches, or is nested in, the given name. Once the loggers are
+	// created, it is changed through SetOverrides.
+	Overrides Overrides
+
+	router *router
+}
+
+// BindFlags binds the zap flags and the log-override flag to the given flag set.
+func (o *LogOptions) BindFlags(fs *flag.FlagSet) {
+	o.Options.BindFlags(fs)
+
+	fs.Var(&o.Overrides, "log-override",
+		"Override the level and/or the format of a named logger and of its children, as name=[level][:format], "+
+			"e.g. controller=debug:console. Can be repeated.")
+}
+
+// Override holds the settings of a named logger, empty fields are inherited
+// from the base options.
+type Override struct {
+	// Level is either one of debug, info, error or an integer value > 0 for
+	// custom debug levels of increasing verbosity.
+	Level string
+	// Format is either json or console.
+	Format string
+}
+
+// Overrides implements flag.Value for overrides expressed as name=[level][:format].
+type Overrides map[string]Override
+
+func (o *Overrides) String() string {
+	if o == nil {
+		return ""
+	}
+
+	items := make([]string, 0, len(*o))
+	for name, override := range *o {
+		item := name + "=" + override.Level
+		if override.Format != "" {
+			item += ":" + override.Format
+		}
+
+		items = append(items, item)
+	}
+
+	sort.Strings(items)
+
+	return strings.Join(items, ",")
+}
+
+func (o *Overrides) Set(value string) error {
+	name, settings, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid log override %q, expected name=[level][:format]", value)
+	}
+
+	level, format, _ := strings.Cut(settings, ":")
+
+	if level != "" {
+		if _, err := parseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "", FormatJSON, FormatConsole:
+	default:
+		return fmt.Errorf("invalid log format %q, expected one of %s or %s", format, FormatJSON, FormatConsole)
+	}
+
+	if *o == nil {
+		*o = make(Overrides)
+	}
+
+	(*o)[name] = Override{
+		Level:  level,
+		Format: format,
+	}
+
+	return nil
+}
+
+// SetLevel changes the level of the loggers without a level override, including
+// the ones already created by New. The value accepts the same levels as overrides.
+func (o *LogOptions) SetLevel(value string) error {
+	level, err := parseLevel(value)
+	if err != nil {
+		return err
+	}
+
+	if current, ok := o.Level.(zap.AtomicLevel); ok {
+		current.SetLevel(level.(zap.AtomicLevel).Level())
+		return nil
+	}
+
+	o.Level = level
+
+	return nil
+}
+
+// SetOverrides replaces the overrides, including for the loggers already created
+// by New. Once the loggers are created, the Overrides field is left untouched.
+func (o *LogOptions) SetOverrides(overrides Overrides) {
+	if o.router == nil {
+		o.Overrides = overrides
+		return
+	}
+
+	o.router.setOverrides(overrides)
+}
+
+// ValidateLevel returns an error if the given value is not a valid level.
+func ValidateLevel(value string) error {
+	_, err := parseLevel(value)
+	return err
+}
+
+// New creates a logger out of the given options. The entries of the named loggers
+// matching an override are written with the overridden level and format.
+func New(o *LogOptions) logr.Logger {
+	// make sure the level is shared by the loggers so that it can be changed
+	// at runtime, see SetLevel
+	if o.Level == nil {
+		level := zapcore.InfoLevel
+		if o.Development {
+			level = zapcore.DebugLevel
+		}
+
+		o.Level = zap.NewAtomicLevelAt(level)
+	}
+
+	r := &router{options: o.Options}
+	r.setOverrides(o.Overrides)
+
+	o.router = r
+
+	l := ctrlzap.NewRaw(ctrlzap.UseFlagOptions(r.copyOptions())).WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		r.base = c
+		return &core{router: r}
+	}))
+
+	return zapr.NewLogger(l)
+}
+
+// router selects the core the entries are written to, out of the name of their
+// logger.
+type router struct {
+	options ctrlzap.Options
+	base    zapcore.Core
+
+	lock      sync.RWMutex
+	overrides map[string]zapcore.Core
+	names     map[string]zapcore.Core
+}
+
+// copyOptions returns a copy of the base options. The slices are appended to
+// when the defaults are computed, so they are not shared among cores.
+func (r *router) copyOptions() *ctrlzap.Options {
+	opts := r.options
+	opts.ZapOpts = append([]zap.Option(nil), opts.ZapOpts...)
+	opts.EncoderConfigOptions = append([]ctrlzap.EncoderConfigOption(nil), opts.EncoderConfigOptions...)
+
+	return &opts
+}
+
+func (r *router) setOverrides(overrides Overrides) {
+	cores := make(map[string]zapcore.Core, len(overrides))
+
+	for name, override := range overrides {
+		opts := r.copyOptions()
+
+		if override.Level != "" {
+			// already validated by Set
+			opts.Level, _ = parseLevel(override.Level)
+		}
+
+		switch override.Format {
+		case FormatJSON:
+			opts.Encoder = nil
+			opts.NewEncoder = newJSONEncoder
+		case FormatConsole:
+			opts.Encoder = nil
+			opts.NewEncoder = newConsoleEncoder
+		}
+
+		cores[name] = ctrlzap.NewRaw(ctrlzap.UseFlagOptions(opts)).Core()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.overrides = cores
+	r.names = make(map[string]zapcore.Core)
+}
+
+// core returns the core of the most specific override matching, or nested in,
+// the given logger name, else the base one.
+func (r *router) core(name string) zapcore.Core {
+	r.lock.RLock()
+	c, ok := r.names[name]
+	r.lock.RUnlock()
+
+	if ok {
+		return c
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	match := ""
+	c = r.base
+
+	for key, override := range r.overrides {
+		if name != key && !strings.HasPrefix(name, key+".") {
+			continue
+		}
+
+		if len(key) > len(match) {
+			match = key
+			c = override
+		}
+	}
+
+	r.names[name] = c
+
+	return c
+}
+
+// enabled returns true if any of the cores is enabled at the given level.
+func (r *router) enabled(level zapcore.Level) bool {
+	if r.base.Enabled(level) {
+		return true
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, c := range r.overrides {
+		if c.Enabled(level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// core is a zapcore.Core writing the entries through the core selected by the
+// router. As the level of a logger depends on its name, Enabled reports whether
+// any logger is enabled at a level and the entries are filtered when checked.
+type core struct {
+	router *router
+	fields []zapcore.Field
+}
+
+var _ zapcore.Core = &core{}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return c.router.enabled(level)
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		router: c.router,
+		fields: append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+// Check delegates to the selected core, which may sample the entries.
+func (c *core) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	target := c.router.core(entry.LoggerName)
+	if len(c.fields) > 0 {
+		target = target.With(c.fields)
+	}
+
+	return target.Check(entry, ce)
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.router.core(entry.LoggerName).Write(entry, append(append([]zapcore.Field(nil), c.fields...), fields...))
+}
+
+func (c *core) Sync() error {
+	return c.router.base.Sync()
+}
+
+// parseLevel mirrors the levels accepted by the zap-log-level flag.
+func parseLevel(value string) (zapcore.LevelEnabler, error) {
+	switch value {
+	case "debug":
+		return zap.NewAtomicLevelAt(zapcore.DebugLevel), nil
+	case "info":
+		return zap.NewAtomicLevelAt(zapcore.InfoLevel), nil
+	case "error":
+		return zap.NewAtomicLevelAt(zapcore.ErrorLevel), nil
+	}
+
+	l, err := strconv.Atoi(value)
+	if err != nil || l <= 0 {
+		return nil, fmt.Errorf("invalid log level %q, expected one of debug, info, error or an integer > 0", value)
+	}
+
+	return zap.NewAtomicLevelAt(zapcore.Level(int8(-l))), nil
+}
+
+func newJSONEncoder(opts ...ctrlzap.EncoderConfigOption) zapcore.Encoder {
+	cfg := zap.NewProductionEncoderConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return zapcore.NewJSONEncoder(cfg)
+}
+
+func newConsoleEncoder(opts ...ctrlzap.EncoderConfigOption) zapcore.Encoder {
+	cfg := zap.NewDevelopmentEncoderConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return zapcore.NewConsoleEncoder(cfg)
+}
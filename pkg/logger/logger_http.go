@@ -2,17 +2,48 @@ package logger
 
 import (
 	"net/http"
+	"net/http/httputil"
+	"strings"
+
+	"github.com/go-logr/logr"
 )
 
+const redacted = "<redacted>"
+
+// sensitiveHeaders are replaced with a placeholder before a request or a
+// response is dumped.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
 // LoggingRoundTripper implements http.RoundTripper.
 // When set as Transport of http.Client, it executes HTTP requests with logging.
 type LoggingRoundTripper struct {
 	Proxied http.RoundTripper
-	// Logger  logr.Logger
+	Logger  logr.Logger
+}
+
+// NewLoggingRoundTripper returns a function suitable to be used with
+// rest.Config.Wrap to install a LoggingRoundTripper on a client config.
+func NewLoggingRoundTripper(l logr.Logger) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return LoggingRoundTripper{
+			Proxied: rt,
+			Logger:  l,
+		}
+	}
 }
 
-// RoundTrip logs the http request and response in debug mode
-// for all errors, where status code >= 400.
+// RoundTrip logs the http request and response
+// for all errors, where status code >= 400. Not found and conflict errors are
+// expected as part of the normal reconcile flow (get before create, optimistic
+// locking) so they are only logged at debug level.
+//
+// Sensitive headers are redacted and the payload of requests targeting
+// secrets is never included.
 func (c LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
 	resp, err := c.Proxied.RoundTrip(r)
 	if err != nil {
@@ -24,21 +55,99 @@ func (c LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error)
 		return resp, nil
 	}
 
-	/*
-		requestDump, err := httputil.DumpRequest(r, true)
+	l := c.Logger
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusConflict {
+		l = l.V(1)
+	}
+
+	if !l.Enabled() {
+		return resp, nil
+	}
+
+	secret := isSecret(r)
+
+	requestDump, err := dumpRequest(r, !secret)
+	if err != nil {
+		c.Logger.Error(err, "unable to dump request", "method", r.Method, "url", r.URL.String())
+	}
+
+	responseDump, err := dumpResponse(resp, !secret)
+	if err != nil {
+		c.Logger.Error(err, "unable to dump response", "method", r.Method, "url", r.URL.String())
+	}
+
+	l.Info("api request failed",
+		"method", r.Method,
+		"url", r.URL.String(),
+		"status", resp.StatusCode,
+		"request", requestDump,
+		"response", responseDump)
+
+	return resp, nil
+}
+
+// dumpRequest dumps a copy of the given request. As the original body has
+// already been consumed by the proxied transport, the body is only included
+// when it can be recreated.
+func dumpRequest(r *http.Request, body bool) (string, error) {
+	clone := r.Clone(r.Context())
+	clone.Header = redactHeaders(r.Header)
+	clone.Body = nil
+
+	if body && r.GetBody != nil {
+		b, err := r.GetBody()
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
-		c.Logger.Info(string(requestDump))
+		clone.Body = b
+	}
 
-		responseDump, err := httputil.DumpResponse(resp, true)
-		if err != nil {
-			return nil, err
+	dump, err := httputil.DumpRequest(clone, clone.Body != nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(dump), nil
+}
+
+// dumpResponse dumps the given response, restoring its body so that it can
+// still be consumed by the caller.
+func dumpResponse(resp *http.Response, body bool) (string, error) {
+	header := resp.Header
+	resp.Header = redactHeaders(header)
+
+	defer func() {
+		resp.Header = header
+	}()
+
+	dump, err := httputil.DumpResponse(resp, body)
+	if err != nil {
+		return "", err
+	}
+
+	return string(dump), nil
+}
+
+func redactHeaders(h http.Header) http.Header {
+	answer := h.Clone()
+
+	for _, name := range sensitiveHeaders {
+		if answer.Get(name) != "" {
+			answer.Set(name, redacted)
 		}
+	}
 
-		c.Logger.Info(string(responseDump))
-	*/
+	return answer
+}
 
-	return resp, nil
+// isSecret returns true if the request targets the core secrets resource.
+func isSecret(r *http.Request) bool {
+	for _, segment := range strings.Split(r.URL.Path, "/") {
+		if segment == "secrets" {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/conflict":
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+
+		_, _ = w.Write([]byte("response-payload"))
+	}))
+	defer server.Close()
+
+	var logs []string
+
+	client := func(verbosity int) http.Client {
+		return http.Client{
+			Transport: NewLoggingRoundTripper(funcr.New(func(prefix, args string) {
+				logs = append(logs, args)
+			}, funcr.Options{Verbosity: verbosity}))(http.DefaultTransport),
+		}
+	}
+
+	c := client(0)
+
+	call := func(path string) string {
+		logs = nil
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader("request-payload"))
+		assert.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := c.Do(req)
+		assert.NoError(t, err)
+		defer func() { _ = resp.Body.Close() }()
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "response-payload", string(body))
+
+		return strings.Join(logs, "\n")
+	}
+
+	out := call("/ok")
+	assert.Empty(t, out)
+
+	out = call("/api/v1/namespaces/ns/configmaps/cm")
+	assert.Contains(t, out, "request-payload")
+	assert.Contains(t, out, "response-payload")
+	assert.Contains(t, out, redacted)
+	assert.NotContains(t, out, "Bearer token")
+
+	out = call("/api/v1/namespaces/ns/secrets/s")
+	assert.NotEmpty(t, out)
+	assert.NotContains(t, out, "request-payload")
+	assert.NotContains(t, out, "response-payload")
+	assert.NotContains(t, out, "Bearer token")
+
+	// expected errors are only logged at debug level
+	out = call("/missing")
+	assert.Empty(t, out)
+
+	out = call("/conflict")
+	assert.Empty(t, out)
+
+	c = client(1)
+
+	out = call("/missing")
+	assert.Contains(t, out, "404")
+}
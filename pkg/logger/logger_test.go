@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestOverridesSet(t *testing.T) {
+	o := Overrides{}
+
+	require.NoError(t, o.Set("controller=debug:json"))
+	require.NoError(t, o.Set("api-client=3"))
+	require.NoError(t, o.Set("events=:console"))
+
+	assert.Equal(t, Overrides{
+		"controller": {Level: "debug", Format: FormatJSON},
+		"api-client": {Level: "3"},
+		"events":     {Format: FormatConsole},
+	}, o)
+
+	assert.Equal(t, "api-client=3,controller=debug:json,events=:console", o.String())
+
+	assert.Error(t, o.Set("controller"))
+	assert.Error(t, o.Set("=debug"))
+	assert.Error(t, o.Set("controller=verbose"))
+	assert.Error(t, o.Set("controller=-1"))
+	assert.Error(t, o.Set("controller=debug:xml"))
+}
+
+func TestNew(t *testing.T) {
+	out := bytes.Buffer{}
+
+	o := LogOptions{
+		Options: ctrlzap.Options{
+			DestWriter:  &out,
+			Development: true,
+		},
+	}
+
+	level, err := parseLevel("info")
+	require.NoError(t, err)
+
+	o.Options.Level = level
+
+	require.NoError(t, o.Overrides.Set("controller=debug:json"))
+	require.NoError(t, o.Overrides.Set("controller.quiet=error"))
+
+	l := New(&o).WithValues("shared", "value")
+
+	lines := func() []string {
+		defer out.Reset()
+		return strings.Split(strings.TrimSpace(out.String()), "\n")
+	}
+
+	// base logger, console and info
+	l.WithName("other").V(1).Info("hidden")
+	l.WithName("other").Info("visible")
+
+	entries := lines()
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0], "other\tvisible")
+
+	// overridden logger, json and debug, inheriting the values
+	l.WithName("controller").WithName("workspace").V(1).Info("debug", "key", "val")
+
+	entries = lines()
+	require.Len(t, entries, 1)
+
+	entry := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(entries[0]), &entry))
+	assert.Equal(t, "controller.workspace", entry["logger"])
+	assert.Equal(t, "debug", entry["msg"])
+	assert.Equal(t, "value", entry["shared"])
+	assert.Equal(t, "val", entry["key"])
+
+	// most specific override wins
+	l.WithName("controller").WithName("quiet").Info("hidden")
+	assert.Empty(t, strings.TrimSpace(out.String()))
+}
+
+func TestSetLevel(t *testing.T) {
+	out := bytes.Buffer{}
+
+	o := LogOptions{
+		Options: ctrlzap.Options{
+			DestWriter: &out,
+		},
+	}
+
+	require.NoError(t, o.Overrides.Set("controller=error"))
+
+	l := New(&o)
+
+	l.V(1).Info("hidden")
+	assert.Empty(t, out.String())
+
+	require.NoError(t, o.SetLevel("debug"))
+
+	// the level applies to the loggers created before the change
+	l.V(1).Info("visible")
+	assert.Contains(t, out.String(), "visible")
+
+	// unless they have a level override
+	out.Reset()
+	l.WithName("controller").Info("hidden")
+	assert.Empty(t, out.String())
+
+	assert.Error(t, o.SetLevel("verbose"))
+	assert.NoError(t, ValidateLevel("2"))
+	assert.Error(t, ValidateLevel("-1"))
+}
+
+func TestSetOverrides(t *testing.T) {
+	out := bytes.Buffer{}
+
+	o := LogOptions{
+		Options: ctrlzap.Options{
+			DestWriter: &out,
+		},
+	}
+
+	require.NoError(t, o.Overrides.Set("controller=error"))
+
+	l := New(&o).WithName("controller").WithValues("shared", "value")
+
+	l.Info("hidden")
+	assert.Empty(t, out.String())
+
+	overrides := Overrides{}
+	require.NoError(t, overrides.Set("controller.workspace=debug:json"))
+
+	o.SetOverrides(overrides)
+
+	// the loggers created before the change switch to the new overrides
+	l.Info("visible")
+	assert.Contains(t, out.String(), "visible")
+
+	out.Reset()
+	l.WithName("workspace").V(1).Info("debug")
+
+	entry := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "controller.workspace", entry["logger"])
+	assert.Equal(t, "value", entry["shared"])
+
+	// the overrides can be removed
+	o.SetOverrides(nil)
+
+	out.Reset()
+	l.WithName("workspace").V(1).Info("hidden")
+	assert.Empty(t, out.String())
+}
+
+func TestCaller(t *testing.T) {
+	out := bytes.Buffer{}
+
+	o := LogOptions{
+		Options: ctrlzap.Options{
+			DestWriter: &out,
+			ZapOpts:    []zap.Option{zap.AddCaller()},
+		},
+	}
+
+	require.NoError(t, o.Overrides.Set("controller=:json"))
+
+	l := New(&o)
+
+	caller := func() string {
+		defer out.Reset()
+
+		entry := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+
+		return entry["caller"].(string)
+	}
+
+	// the caller is the line before the one returning the expected value
+	expected := func() string {
+		_, file, line, _ := runtime.Caller(1)
+		return fmt.Sprintf("logger/%s:%d", filepath.Base(file), line-1)
+	}
+
+	for _, logger := range []logr.Logger{l, l.WithName("other"), l.WithName("controller").WithValues("key", "value")} {
+		logger.Info("info")
+		assert.Equal(t, expected(), caller())
+
+		logger.Error(errors.New("failure"), "error")
+		assert.Equal(t, expected(), caller())
+	}
+
+	// helpers account for their own frame
+	helper := func(logger logr.Logger) {
+		logger.WithCallDepth(1).Info("helper")
+	}
+
+	helper(l.WithName("controller"))
+	assert.Equal(t, expected(), caller())
+}
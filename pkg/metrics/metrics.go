@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	Namespace = "sco"
+
+	OperationApply   = "apply"
+	OperationCleanup = "cleanup"
+
+	MigrationResultMigrated = "migrated"
+	MigrationResultSkipped  = "skipped"
+	MigrationResultFailed   = "failed"
+)
+
+var (
+	ActionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "action",
+			Name:      "duration_seconds",
+			Help:      "Duration of the operations performed by reconcile actions.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"action", "operation"},
+	)
+
+	ActionFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "action",
+			Name:      "failures_total",
+			Help:      "Number of failed operations performed by reconcile actions, by reason.",
+		},
+		[]string{"action", "operation", "reason"},
+	)
+
+	APIClientRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "api_client",
+			Name:      "requests_total",
+			Help:      "Number of requests performed against the API server, by resource, verb and status code.",
+		},
+		[]string{"group", "version", "resource", "verb", "code"},
+	)
+
+	APIClientDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "api_client",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests performed against the API server, by resource and verb.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"group", "version", "resource", "verb"},
+	)
+
+	StorageMigrationObjects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "storage_migration",
+			Name:      "objects_total",
+			Help:      "Number of objects processed by the storage version migration, by resource and result.",
+		},
+		[]string{"resource", "result"},
+	)
+
+	StorageMigrationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "storage_migration",
+			Name:      "duration_seconds",
+			Help:      "Duration of the storage version migrations, by resource.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 8),
+		},
+		[]string{"resource"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ActionDuration,
+		ActionFailures,
+		APIClientRequests,
+		APIClientDuration,
+		StorageMigrationObjects,
+		StorageMigrationDuration,
+	)
+}
+
+// Reason returns a low cardinality reason for the given error, suitable to be used
+// as metric label.
+func Reason(err error) string {
+	if reason := k8serrors.ReasonForError(err); reason != "" {
+		return string(reason)
+	}
+
+	return "Unknown"
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CodeError is used as code label for requests that did not get any response.
+const CodeError = "error"
+
+// RoundTripper implements http.RoundTripper.
+// When set as Transport of http.Client, it records the number and the latency
+// of the requests performed against the API server.
+type RoundTripper struct {
+	Proxied http.RoundTripper
+}
+
+// NewRoundTripper returns a function suitable to be used with rest.Config.Wrap
+// to install a RoundTripper on a client config.
+func NewRoundTripper() func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripper{
+			Proxied: rt,
+		}
+	}
+}
+
+func (c RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	info := requestInfoFor(r)
+	start := time.Now()
+
+	resp, err := c.Proxied.RoundTrip(r)
+
+	APIClientDuration.WithLabelValues(info.Group, info.Version, info.Resource, info.Verb).Observe(time.Since(start).Seconds())
+
+	code := CodeError
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	APIClientRequests.WithLabelValues(info.Group, info.Version, info.Resource, info.Verb, code).Inc()
+
+	return resp, err
+}
+
+// requestInfo holds the low cardinality attributes of an API request.
+type requestInfo struct {
+	Group    string
+	Version  string
+	Resource string
+	Verb     string
+}
+
+// requestInfoFor extracts the group, version, resource and verb targeted by
+// the given request, following the Kubernetes API path conventions:
+//
+//	/api/{version}/[namespaces/{namespace}/]{resource}[/{name}[/{subresource}]]
+//	/apis/{group}/{version}/[namespaces/{namespace}/]{resource}[/{name}[/{subresource}]]
+//
+// Discovery requests (i.e. /api, /apis, /apis/{group}/{version}) have no
+// resource.
+func requestInfoFor(r *http.Request) requestInfo {
+	info := requestInfo{}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		info.Version = parts[1]
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		info.Group = parts[1]
+		info.Version = parts[2]
+		parts = parts[3:]
+	default:
+		parts = nil
+	}
+
+	// strip the namespace scope, unless the request targets a namespace
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+
+	name := ""
+
+	switch len(parts) {
+	case 0:
+	case 1:
+		info.Resource = parts[0]
+	case 2:
+		info.Resource = parts[0]
+		name = parts[1]
+	default:
+		info.Resource = parts[0] + "/" + parts[2]
+		name = parts[1]
+	}
+
+	info.Verb = verbFor(r, info.Resource, name)
+
+	return info
+}
+
+func verbFor(r *http.Request, resource string, name string) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		switch {
+		case resource == "":
+			return "get"
+		case r.URL.Query().Get("watch") == "true":
+			return "watch"
+		case name == "":
+			return "list"
+		default:
+			return "get"
+		}
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		if r.Header.Get("Content-Type") == string(types.ApplyPatchType) {
+			return "apply"
+		}
+
+		return "patch"
+	case http.MethodDelete:
+		if name == "" {
+			return "deletecollection"
+		}
+
+		return "delete"
+	default:
+		return strings.ToLower(r.Method)
+	}
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRequestInfoFor(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		expected    requestInfo
+	}{
+		{
+			name:     "discovery",
+			method:   http.MethodGet,
+			path:     "/apis/camel.apache.org/v1",
+			expected: requestInfo{Group: "camel.apache.org", Version: "v1", Verb: "get"},
+		},
+		{
+			name:     "core list",
+			method:   http.MethodGet,
+			path:     "/api/v1/namespaces/ns/configmaps",
+			expected: requestInfo{Version: "v1", Resource: "configmaps", Verb: "list"},
+		},
+		{
+			name:     "namespace get",
+			method:   http.MethodGet,
+			path:     "/api/v1/namespaces/ns",
+			expected: requestInfo{Version: "v1", Resource: "namespaces", Verb: "get"},
+		},
+		{
+			name:     "watch",
+			method:   http.MethodGet,
+			path:     "/apis/camel.apache.org/v1/namespaces/ns/integrationplatforms?watch=true",
+			expected: requestInfo{Group: "camel.apache.org", Version: "v1", Resource: "integrationplatforms", Verb: "watch"},
+		},
+		{
+			name:        "apply",
+			method:      http.MethodPatch,
+			path:        "/apis/camel.apache.org/v1/namespaces/ns/integrationplatforms/ws",
+			contentType: string(types.ApplyPatchType),
+			expected:    requestInfo{Group: "camel.apache.org", Version: "v1", Resource: "integrationplatforms", Verb: "apply"},
+		},
+		{
+			name:        "merge patch subresource",
+			method:      http.MethodPatch,
+			path:        "/apis/camel.apache.org/v1/namespaces/ns/integrationplatforms/ws/status",
+			contentType: string(types.MergePatchType),
+			expected:    requestInfo{Group: "camel.apache.org", Version: "v1", Resource: "integrationplatforms/status", Verb: "patch"},
+		},
+		{
+			name:     "delete collection",
+			method:   http.MethodDelete,
+			path:     "/apis/route.openshift.io/v1/namespaces/ns/routes",
+			expected: requestInfo{Group: "route.openshift.io", Version: "v1", Resource: "routes", Verb: "deletecollection"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, test.path, nil)
+			if test.contentType != "" {
+				r.Header.Set("Content-Type", test.contentType)
+			}
+
+			assert.Equal(t, test.expected, requestInfoFor(r))
+		})
+	}
+}
+
+func TestRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := http.Client{
+		Transport: NewRoundTripper()(http.DefaultTransport),
+	}
+
+	counter := APIClientRequests.WithLabelValues("sco.test", "v1", "things", "get", "404")
+	before := testutil.ToFloat64(counter)
+
+	resp, err := c.Get(server.URL + "/apis/sco.test/v1/namespaces/ns/things/t")
+	assert.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
@@ -0,0 +1,260 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/sco1237896/sco-operator/pkg/metrics"
+)
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update;patch
+
+// DefaultPageSize is the number of objects listed at once when none is configured.
+const DefaultPageSize = 100
+
+// Migrator rewrites the objects of custom resources in their storage version, so
+// that the versions that are not served anymore can be removed from the stored
+// versions of their definition.
+type Migrator struct {
+	CRDs    apiextensions.Interface
+	Dynamic dynamic.Interface
+	Log     logr.Logger
+
+	// PageSize is the number of objects listed at once, defaults to DefaultPageSize.
+	PageSize int64
+}
+
+// Result reports the outcome of the migration of a custom resource.
+type Result struct {
+	StorageVersion string
+	Migrated       int
+	Skipped        int
+	Failed         int
+
+	// Blocking holds the <namespace>/<name> of the objects that failed to migrate,
+	// which keep the stored versions from being trimmed.
+	Blocking []string
+}
+
+func New(config *rest.Config, log logr.Logger) (*Migrator, error) {
+	cfg := rest.CopyConfig(config)
+	cfg.Wrap(metrics.NewRoundTripper())
+
+	crds, err := apiextensions.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := Migrator{
+		CRDs:     crds,
+		Dynamic:  dynamicClient,
+		Log:      log,
+		PageSize: DefaultPageSize,
+	}
+
+	return &m, nil
+}
+
+// Runnable returns a runnable migrating the CRDs with the given names once the manager
+// is elected leader. Failures are logged and do not stop the manager.
+func (m *Migrator) Runnable(names ...string) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		for _, name := range names {
+			if _, err := m.Migrate(ctx, name); err != nil {
+				m.Log.Error(err, "storage version migration failed", "crd", name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Migrate rewrites all the objects of the custom resource defined by the CRD with
+// the given name in its storage version, then trims the stored versions of the CRD
+// down to the storage version. Nothing is done if the storage version is the only
+// stored version. The stored versions are kept when any object fails to migrate, so
+// that the migration can be run again.
+func (m *Migrator) Migrate(ctx context.Context, name string) (Result, error) {
+	crd, err := m.CRDs.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		StorageVersion: StorageVersion(crd),
+	}
+
+	if result.StorageVersion == "" {
+		return result, fmt.Errorf("no storage version found for %s", name)
+	}
+
+	log := m.Log.WithValues("crd", name, "storageVersion", result.StorageVersion)
+
+	if !NeedsMigration(crd) {
+		log.Info("storage version migration not needed", "storedVersions", crd.Status.StoredVersions)
+		return result, nil
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    crd.Spec.Group,
+		Version:  result.StorageVersion,
+		Resource: crd.Spec.Names.Plural,
+	}
+
+	log.Info("storage version migration started", "storedVersions", crd.Status.StoredVersions)
+
+	start := time.Now()
+
+	defer func() {
+		metrics.StorageMigrationDuration.WithLabelValues(gvr.Resource).Observe(time.Since(start).Seconds())
+	}()
+
+	pageSize := m.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	opts := metav1.ListOptions{
+		Limit: pageSize,
+	}
+
+	for {
+		list, err := m.Dynamic.Resource(gvr).List(ctx, opts)
+		if err != nil {
+			return result, err
+		}
+
+		for i := range list.Items {
+			err := m.migrate(ctx, gvr, &list.Items[i])
+
+			switch {
+			case k8serrors.IsNotFound(err):
+				result.Skipped++
+				metrics.StorageMigrationObjects.WithLabelValues(gvr.Resource, metrics.MigrationResultSkipped).Inc()
+			case err != nil:
+				result.Failed++
+				result.Blocking = append(result.Blocking, list.Items[i].GetNamespace()+"/"+list.Items[i].GetName())
+				metrics.StorageMigrationObjects.WithLabelValues(gvr.Resource, metrics.MigrationResultFailed).Inc()
+
+				log.Error(err, "unable to migrate object", "namespace", list.Items[i].GetNamespace(), "name", list.Items[i].GetName())
+			default:
+				result.Migrated++
+				metrics.StorageMigrationObjects.WithLabelValues(gvr.Resource, metrics.MigrationResultMigrated).Inc()
+			}
+		}
+
+		log.Info("storage version migration in progress", "migrated", result.Migrated, "skipped", result.Skipped, "failed", result.Failed)
+
+		opts.Continue = list.GetContinue()
+		if opts.Continue == "" {
+			break
+		}
+	}
+
+	if result.Failed > 0 {
+		// objects rejected by the admission webhooks, e.g. legacy objects that are not
+		// valid anymore, have to be repaired manually before running the migration again
+		log.Info("stored versions kept, some objects failed to migrate", "storedVersions", crd.Status.StoredVersions, "objects", result.Blocking)
+
+		return result, fmt.Errorf("unable to migrate %d objects of %s: %s", result.Failed, name, strings.Join(result.Blocking, ", "))
+	}
+
+	if err := m.trim(ctx, name, result.StorageVersion); err != nil {
+		return result, err
+	}
+
+	log.Info("storage version migration completed", "migrated", result.Migrated, "skipped", result.Skipped)
+
+	return result, nil
+}
+
+// migrate rewrites the given object, an update without changes is enough for the
+// API server to store it again in the storage version. The update goes through the
+// admission webhooks, which let the requests of the operator through unchanged.
+func (m *Migrator) migrate(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	client := m.Dynamic.Resource(gvr).Namespace(obj.GetNamespace())
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := client.Update(ctx, obj, metav1.UpdateOptions{})
+		if !k8serrors.IsConflict(err) {
+			return err
+		}
+
+		// the object has been updated meanwhile, retry with its latest revision
+		latest, getErr := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+
+		obj = latest
+
+		return err
+	})
+}
+
+// trim sets the storage version as the only stored version of the CRD.
+func (m *Migrator) trim(ctx context.Context, name string, version string) error {
+	client := m.CRDs.ApiextensionsV1().CustomResourceDefinitions()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		// the storage version changed meanwhile, e.g. because of an upgrade
+		if StorageVersion(crd) != version {
+			return fmt.Errorf("storage version of %s changed from %s to %s", name, version, StorageVersion(crd))
+		}
+
+		crd.Status.StoredVersions = []string{version}
+
+		_, err = client.UpdateStatus(ctx, crd, metav1.UpdateOptions{})
+
+		return err
+	})
+}
+
+// StorageVersion returns the name of the version used to persist the objects of the
+// given CRD.
+func StorageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+
+	return ""
+}
+
+// NeedsMigration returns true when objects of the given CRD may be persisted in a
+// version other than the storage version.
+func NeedsMigration(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	version := StorageVersion(crd)
+
+	for _, v := range crd.Status.StoredVersions {
+		if v != version {
+			return true
+		}
+	}
+
+	return false
+}
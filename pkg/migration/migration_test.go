@@ -0,0 +1,195 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/sco1237896/sco-operator/pkg/metrics"
+)
+
+const crdName = "workspaces.sco.sco1237896.github.com"
+
+var workspaces = schema.GroupVersionResource{Group: "sco.sco1237896.github.com", Version: "v1beta1", Resource: "workspaces"}
+
+func crd(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: crdName,
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: workspaces.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural: workspaces.Resource,
+				Kind:   "Workspace",
+			},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1alpha1", Served: true},
+				{Name: "v1beta1", Served: true, Storage: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			StoredVersions: storedVersions,
+		},
+	}
+}
+
+func workspace(namespace string, name string) runtime.Object {
+	obj := unstructured.Unstructured{}
+	obj.SetGroupVersionKind(workspaces.GroupVersion().WithKind("Workspace"))
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	return &obj
+}
+
+func migrator(crd *apiextensionsv1.CustomResourceDefinition, objs ...runtime.Object) (*Migrator, *dynamicfake.FakeDynamicClient) {
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{workspaces: "WorkspaceList"},
+		objs...,
+	)
+
+	m := Migrator{
+		CRDs:    apiextensionsfake.NewSimpleClientset(crd),
+		Dynamic: dc,
+		Log:     logr.Discard(),
+	}
+
+	return &m, dc
+}
+
+func updates(dc *dynamicfake.FakeDynamicClient) int {
+	count := 0
+
+	for _, a := range dc.Actions() {
+		if a.GetVerb() == "update" {
+			count++
+		}
+	}
+
+	return count
+}
+
+func storedVersions(t *testing.T, m *Migrator) []string {
+	t.Helper()
+
+	crd, err := m.CRDs.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	return crd.Status.StoredVersions
+}
+
+func TestStorageVersion(t *testing.T) {
+	assert.Equal(t, "v1beta1", StorageVersion(crd()))
+	assert.Empty(t, StorageVersion(&apiextensionsv1.CustomResourceDefinition{}))
+
+	assert.False(t, NeedsMigration(crd("v1beta1")))
+	assert.True(t, NeedsMigration(crd("v1alpha1", "v1beta1")))
+	assert.True(t, NeedsMigration(crd("v1alpha1")))
+}
+
+func TestMigrate(t *testing.T) {
+	m, dc := migrator(crd("v1alpha1", "v1beta1"), workspace("ns1", "ws"), workspace("ns2", "ws"))
+
+	migrated := testutil.ToFloat64(metrics.StorageMigrationObjects.WithLabelValues(workspaces.Resource, metrics.MigrationResultMigrated))
+
+	result, err := m.Migrate(context.Background(), crdName)
+	require.NoError(t, err)
+
+	assert.Equal(t, Result{StorageVersion: "v1beta1", Migrated: 2}, result)
+	assert.Equal(t, 2, updates(dc))
+	assert.Equal(t, []string{"v1beta1"}, storedVersions(t, m))
+	assert.Equal(t, migrated+2, testutil.ToFloat64(metrics.StorageMigrationObjects.WithLabelValues(workspaces.Resource, metrics.MigrationResultMigrated)))
+
+	// nothing to do once migrated
+	dc.ClearActions()
+
+	result, err = m.Migrate(context.Background(), crdName)
+	require.NoError(t, err)
+
+	assert.Equal(t, Result{StorageVersion: "v1beta1"}, result)
+	assert.Empty(t, dc.Actions())
+}
+
+func TestMigrateFailure(t *testing.T) {
+	m, dc := migrator(crd("v1alpha1", "v1beta1"), workspace("ns1", "ws"), workspace("ns2", "ws"), workspace("ns3", "ws"))
+
+	dc.PrependReactor("update", "workspaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch action.GetNamespace() {
+		case "ns1":
+			return true, nil, k8serrors.NewForbidden(workspaces.GroupResource(), "ws", nil)
+		case "ns2":
+			return true, nil, k8serrors.NewNotFound(workspaces.GroupResource(), "ws")
+		default:
+			return false, nil, nil
+		}
+	})
+
+	result, err := m.Migrate(context.Background(), crdName)
+	require.Error(t, err)
+
+	assert.Equal(t, Result{StorageVersion: "v1beta1", Migrated: 1, Skipped: 1, Failed: 1, Blocking: []string{"ns1/ws"}}, result)
+
+	// the stored versions are kept so that the migration can be run again
+	assert.Equal(t, []string{"v1alpha1", "v1beta1"}, storedVersions(t, m))
+}
+
+func TestMigrateRejected(t *testing.T) {
+	m, dc := migrator(crd("v1alpha1", "v1beta1"), workspace("ns1", "ws"), workspace("ns2", "ws"))
+
+	// a legacy object that is not valid anymore, when the webhooks do not trust the migrator
+	dc.PrependReactor("update", "workspaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "ns2" {
+			return false, nil, nil
+		}
+
+		return true, nil, k8serrors.NewInvalid(
+			schema.GroupKind{Group: workspaces.Group, Kind: "Workspace"},
+			"ws",
+			field.ErrorList{field.Invalid(field.NewPath("spec", "expose", "path"), "api", "must start with /")})
+	})
+
+	result, err := m.Migrate(context.Background(), crdName)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "ns2/ws")
+
+	assert.Equal(t, Result{StorageVersion: "v1beta1", Migrated: 1, Failed: 1, Blocking: []string{"ns2/ws"}}, result)
+	assert.Equal(t, []string{"v1alpha1", "v1beta1"}, storedVersions(t, m))
+}
+
+func TestMigrateConflict(t *testing.T) {
+	m, dc := migrator(crd("v1alpha1", "v1beta1"), workspace("ns", "ws"))
+
+	conflicts := 0
+
+	dc.PrependReactor("update", "workspaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			conflicts++
+			return true, nil, k8serrors.NewConflict(workspaces.GroupResource(), "ws", nil)
+		}
+
+		return false, nil, nil
+	})
+
+	result, err := m.Migrate(context.Background(), crdName)
+	require.NoError(t, err)
+
+	assert.Equal(t, Result{StorageVersion: "v1beta1", Migrated: 1}, result)
+	assert.Equal(t, 2, updates(dc))
+	assert.Equal(t, []string{"v1beta1"}, storedVersions(t, m))
+}
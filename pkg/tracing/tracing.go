@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	ServiceName = "sco-operator"
+
+	instrumentationName = "github.com/sco1237896/sco-operator"
+
+	otlpTracesPath = "/v1/traces"
+)
+
+// Attributes used to tag the spans related to a Workspace.
+const (
+	AttributeWorkspaceName       = attribute.Key("sco.workspace.name")
+	AttributeWorkspaceGeneration = attribute.Key("sco.workspace.generation")
+	AttributeAction              = attribute.Key("sco.action")
+)
+
+type Options struct {
+	// Exporter is one of none, otlp, stdout or file.
+	Exporter string
+	// Endpoint is the base URL of the OTLP/HTTP collector, e.g. http://localhost:4318.
+	Endpoint string
+	// File is the path spans are written to when using the file exporter.
+	File string
+}
+
+// Setup installs the global tracer provider according to the given options and
+// returns a function to flush and stop it. With the none exporter the global
+// no-op provider is left in place.
+func Setup(options Options) (func(context.Context) error, error) {
+	shutdown := func(context.Context) error { return nil }
+
+	var exporter sdktrace.SpanExporter
+
+	switch options.Exporter {
+	case "", ExporterNone:
+		return shutdown, nil
+	case ExporterOTLP:
+		if options.Endpoint == "" {
+			return nil, fmt.Errorf("an endpoint is required by the %s tracing exporter", ExporterOTLP)
+		}
+
+		e, err := newOTLPExporter(options.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter = e
+	case ExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+
+		exporter = e
+	case ExporterFile:
+		if options.File == "" {
+			return nil, fmt.Errorf("a file is required by the %s tracing exporter", ExporterFile)
+		}
+
+		f, err := os.OpenFile(options.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+
+		exporter = e
+		shutdown = func(context.Context) error { return f.Close() }
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q", options.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter)),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(ServiceName))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if serr := shutdown(ctx); err == nil {
+			err = serr
+		}
+
+		return err
+	}, nil
+}
+
+// newOTLPExporter returns an exporter sending spans to the OTLP/HTTP collector at
+// the given base URL, e.g. http://localhost:4318.
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tracing endpoint %q: %w", endpoint, err)
+	}
+
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid tracing endpoint %q: an http or https URL is required", endpoint)
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + otlpTracesPath),
+	}
+
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	// the exporter connects lazily, hence the context is not used
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// Start creates a span using the global tracer provider.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End records the given error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RoundTripper implements http.RoundTripper.
+// When set as Transport of http.Client, it traces every request as a child of
+// the span found in the request context and propagates the trace context to
+// the server. Requests without a parent span, such as the ones issued by the
+// informers or by the leader election, are not traced so that they do not
+// bury the reconcile traces under a stream of root spans.
+type RoundTripper struct {
+	Proxied http.RoundTripper
+}
+
+// NewRoundTripper returns a function suitable to be used with rest.Config.Wrap
+// to install a RoundTripper on a client config.
+func NewRoundTripper() func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripper{
+			Proxied: rt,
+		}
+	}
+}
+
+func (c RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(r.Context()).IsValid() {
+		return c.Proxied.RoundTrip(r)
+	}
+
+	ctx, span := Start(r.Context(), "HTTP "+r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethod(r.Method),
+			semconv.HTTPURL(r.URL.String()),
+		))
+
+	defer span.End()
+
+	// the request must not be modified, hence inject the trace context in a copy
+	req := r.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := c.Proxied.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetupOTLP(t *testing.T) {
+	defer func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	}()
+
+	requests := make(chan *http.Request, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+	}))
+	defer server.Close()
+
+	shutdown, err := Setup(Options{Exporter: ExporterOTLP, Endpoint: server.URL + "/"})
+	require.NoError(t, err)
+
+	_, span := Start(context.Background(), "span")
+	span.End()
+
+	// flushes the pending spans
+	require.NoError(t, shutdown(context.Background()))
+
+	r := <-requests
+	assert.Equal(t, otlpTracesPath, r.URL.Path)
+	assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+}
+
+func TestSetupInvalid(t *testing.T) {
+	_, err := Setup(Options{Exporter: ExporterOTLP})
+	assert.Error(t, err)
+
+	_, err = Setup(Options{Exporter: ExporterOTLP, Endpoint: "localhost:4318"})
+	assert.Error(t, err)
+
+	_, err = Setup(Options{Exporter: "unknown"})
+	assert.Error(t, err)
+}
@@ -13,8 +13,8 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 
+	"github.com/sco1237896/sco-operator/pkg/capabilities"
 	scoClient "github.com/sco1237896/sco-operator/pkg/client/sco/clientset/versioned"
-	"github.com/sco1237896/sco-operator/pkg/controller/client"
 )
 
 type Client struct {
@@ -67,12 +67,12 @@ func newClient() (*Client, error) {
 		config:    cfg,
 	}
 
-	io, err := client.IsOpenShift(discoveryClient)
+	caps, err := capabilities.Discover(discoveryClient)
 	if err != nil {
 		return nil, err
 	}
 
-	if io {
+	if caps.IsOpenShift() {
 		routeClient, err := route.NewForConfig(cfg)
 		if err != nil {
 			return nil, err